@@ -1,7 +1,10 @@
 package builder
 
 import (
+	"errors"
 	"fmt"
+	"go/format"
+	"go/scanner"
 	"strings"
 )
 
@@ -9,6 +12,7 @@ import (
 type CodeBuilder struct {
 	content     strings.Builder
 	indentLevel int
+	err         error // First error from the literal helpers
 }
 
 // NewCodeBuilder creates a new code builder
@@ -59,3 +63,66 @@ func (cb *CodeBuilder) String() string {
 	return cb.content.String()
 }
 
+// FormatError reports generated source that go/format could not parse
+type FormatError struct {
+	Err     error
+	Snippet string // Offending lines of generated source, numbered
+}
+
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("%v\n%s", e.Err, e.Snippet)
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// Format returns the built code run through gofmt, or the first error
+// recorded by the literal helpers
+func (cb *CodeBuilder) Format() (string, error) {
+	if cb.err != nil {
+		return "", cb.err
+	}
+	return FormatSource(cb.String())
+}
+
+// FormatSource runs src through gofmt. When src does not parse, the
+// returned *FormatError carries the lines surrounding the first error.
+func FormatSource(src string) (string, error) {
+	out, err := format.Source([]byte(src))
+	if err != nil {
+		return "", &FormatError{Err: err, Snippet: snippet(src, errorLine(err))}
+	}
+	return string(out), nil
+}
+
+// snippetContext is the number of lines shown either side of a format error
+const snippetContext = 3
+
+func errorLine(err error) int {
+	var list scanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		return list[0].Pos.Line
+	}
+	return 0
+}
+
+func snippet(src string, line int) string {
+	lines := strings.Split(src, "\n")
+
+	start, end := 1, len(lines)
+	if line > 0 {
+		start = max(line-snippetContext, 1)
+		end = min(line+snippetContext, len(lines))
+	}
+
+	var sb strings.Builder
+	for n := start; n <= end; n++ {
+		marker := "  "
+		if n == line {
+			marker = "> "
+		}
+		fmt.Fprintf(&sb, "%s%4d | %s\n", marker, n, lines[n-1])
+	}
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormat_Gofmt(t *testing.T) {
+	cb := NewCodeBuilder()
+	cb.Writeln("package p")
+	cb.Newline()
+	cb.Writeln("func f(x int) bool {")
+	cb.Indent()
+	cb.Writeln("if x>0 {")
+	cb.Indent()
+	cb.Writeln("return true")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Writeln("return false")
+	cb.Dedent()
+	cb.Writeln("}")
+
+	got, err := cb.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "package p\n\nfunc f(x int) bool {\n\tif x > 0 {\n\t\treturn true\n\t}\n\treturn false\n}\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatSource_Snippet(t *testing.T) {
+	src := strings.Join([]string{
+		"package p",
+		"",
+		"func f() {",
+		"    a := 1",
+		"    if a == {",
+		"    }",
+		"}",
+		"",
+		"func g() {}",
+	}, "\n")
+
+	_, err := FormatSource(src)
+
+	var ferr *FormatError
+	if !errors.As(err, &ferr) {
+		t.Fatalf("expected *FormatError, got %v", err)
+	}
+	if !strings.Contains(ferr.Snippet, ">    5 |     if a == {") {
+		t.Errorf("snippet does not mark the offending line:\n%s", ferr.Snippet)
+	}
+	if !strings.Contains(ferr.Snippet, "   2 | ") || strings.Contains(ferr.Snippet, "   9 | ") {
+		t.Errorf("snippet does not show %d lines of context:\n%s", snippetContext, ferr.Snippet)
+	}
+	if !strings.Contains(err.Error(), ferr.Snippet) {
+		t.Errorf("error message does not include the snippet: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"fmt"
+	"go/token"
+	"strconv"
+)
+
+// invalidIdent stands in for a rejected identifier so the source still parses
+const invalidIdent = "_"
+
+// Quote returns s as a Go string literal, safe to splice into any expression
+func (cb *CodeBuilder) Quote(s string) string {
+	return strconv.Quote(s)
+}
+
+// Ident returns name for use as an identifier. A name that is not a Go
+// identifier is recorded as an error, returned by Err and Format.
+func (cb *CodeBuilder) Ident(name string) string {
+	if !token.IsIdentifier(name) {
+		cb.fail(fmt.Errorf("invalid identifier %q", name))
+		return invalidIdent
+	}
+	return name
+}
+
+// Int returns s as a decimal integer literal. Anything else, such as
+// "0 || true", is recorded as an error, returned by Err and Format.
+func (cb *CodeBuilder) Int(s string) string {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return strconv.FormatInt(n, 10)
+	}
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return strconv.FormatUint(n, 10)
+	}
+
+	cb.fail(fmt.Errorf("invalid integer literal %q", s))
+	return "0"
+}
+
+// Err returns the first error recorded by the literal helpers
+func (cb *CodeBuilder) Err() error {
+	return cb.err
+}
+
+func (cb *CodeBuilder) fail(err error) {
+	if cb.err == nil {
+		cb.err = err
+	}
+}
@@ -0,0 +1,136 @@
+package configfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the config files looked for next to go.mod, in order
+var FileNames = []string{"valforge.yaml", "valforge.yml", "valforge.toml"}
+
+// Settings are the options that can be set for the whole project or
+// overridden for a single package
+type Settings struct {
+	Output   string            `yaml:"output" toml:"output"`       // Output file name pattern, e.g. "{name}_validation.gen.go"
+	Tag      string            `yaml:"tag" toml:"tag"`             // Struct tag holding validation rules
+	NameFrom string            `yaml:"name_from" toml:"name_from"` // Tag naming fields in errors, or "go"
+	Messages map[string]string `yaml:"messages" toml:"messages"`   // Message templates keyed by message code
+	Rules    []string          `yaml:"rules" toml:"rules"`         // Enabled rules, all when empty
+	Receiver string            `yaml:"receiver" toml:"receiver"`   // Validate receiver kind: value, pointer or auto
+	Groups   []string          `yaml:"groups" toml:"groups"`       // Known validation groups, any name when empty
+}
+
+// File is a parsed valforge.yaml or valforge.toml
+type File struct {
+	Path string `yaml:"-" toml:"-"`
+
+	SupportPackage string `yaml:"support_package" toml:"support_package"`
+	SupportPath    string `yaml:"support_path" toml:"support_path"`
+	GRPC           bool   `yaml:"grpc" toml:"grpc"`             // Emit gRPC status conversion in the support package
+	RulesFile      string `yaml:"rules_file" toml:"rules_file"` // Sidecar file declaring rules outside struct tags
+	Context        bool   `yaml:"context" toml:"context"`       // Emit ValidateContext(ctx) alongside Validate()
+	Length         string `yaml:"length" toml:"length"`         // What string length rules count: runes, bytes or graphemes
+
+	Settings `yaml:",inline"`
+
+	// Packages holds per-package overrides keyed by directory relative to the project root
+	Packages map[string]Settings `yaml:"packages" toml:"packages"`
+}
+
+// Find returns the first config file present in dir, or "" when there is none
+func Find(dir string) string {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Load reads a config file, choosing the format from its extension
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{Path: path}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(f); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("%s: unknown key '%s'", path, undecoded[0])
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported config format", path)
+	}
+
+	if f.SupportPath != "" && !filepath.IsAbs(f.SupportPath) {
+		f.SupportPath = filepath.Join(filepath.Dir(path), f.SupportPath)
+	}
+	if f.RulesFile != "" && !filepath.IsAbs(f.RulesFile) {
+		f.RulesFile = filepath.Join(filepath.Dir(path), f.RulesFile)
+	}
+
+	return f, nil
+}
+
+// For returns the settings that apply to the package in dir, which is
+// relative to the project root. Package overrides replace the project
+// settings they set, and message templates are merged.
+func (f *File) For(dir string) Settings {
+	s := f.Settings
+	override, ok := f.Packages[filepath.ToSlash(filepath.Clean(dir))]
+	if !ok {
+		return s
+	}
+
+	if override.Output != "" {
+		s.Output = override.Output
+	}
+	if override.Tag != "" {
+		s.Tag = override.Tag
+	}
+	if override.NameFrom != "" {
+		s.NameFrom = override.NameFrom
+	}
+	if override.Receiver != "" {
+		s.Receiver = override.Receiver
+	}
+	if override.Groups != nil {
+		s.Groups = override.Groups
+	}
+	if override.Rules != nil {
+		s.Rules = override.Rules
+	}
+	if len(override.Messages) > 0 {
+		merged := make(map[string]string, len(s.Messages)+len(override.Messages))
+		for code, tmpl := range s.Messages {
+			merged[code] = tmpl
+		}
+		for code, tmpl := range override.Messages {
+			merged[code] = tmpl
+		}
+		s.Messages = merged
+	}
+
+	return s
+}
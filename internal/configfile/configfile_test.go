@@ -0,0 +1,100 @@
+package configfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_YAMLAndTOML(t *testing.T) {
+	files := map[string]string{
+		"valforge.yaml": `
+support_path: internal/checks
+tag: vf
+messages:
+  required: "{field} must be provided"
+packages:
+  forms:
+    name_from: form
+`,
+		"valforge.toml": `
+support_path = "internal/checks"
+tag = "vf"
+
+[messages]
+required = "{field} must be provided"
+
+[packages.forms]
+name_from = "form"
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfig(t, name, content)
+			f, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := filepath.Join(filepath.Dir(path), "internal/checks"); f.SupportPath != want {
+				t.Errorf("got support path %q, want %q", f.SupportPath, want)
+			}
+			if f.Tag != "vf" || f.Messages["required"] != "{field} must be provided" {
+				t.Errorf("project settings not loaded: %+v", f.Settings)
+			}
+			if f.Packages["forms"].NameFrom != "form" {
+				t.Errorf("package settings not loaded: %+v", f.Packages)
+			}
+		})
+	}
+}
+
+func TestLoad_UnknownKey(t *testing.T) {
+	for name, content := range map[string]string{
+		"valforge.yaml": "tags: vf\n",
+		"valforge.toml": "tags = \"vf\"\n",
+	} {
+		if _, err := Load(writeConfig(t, name, content)); err == nil || !strings.Contains(err.Error(), "tags") {
+			t.Errorf("%s: expected an error naming the unknown key, got %v", name, err)
+		}
+	}
+}
+
+func TestFor_PackageOverrides(t *testing.T) {
+	f := &File{
+		Settings: Settings{
+			Tag:      "validate",
+			NameFrom: "json",
+			Messages: map[string]string{"required": "project", "email": "project"},
+		},
+		Packages: map[string]Settings{
+			"internal/forms": {NameFrom: "form", Messages: map[string]string{"required": "forms"}},
+		},
+	}
+
+	got := f.For("internal/forms/")
+	want := Settings{
+		Tag:      "validate",
+		NameFrom: "form",
+		Messages: map[string]string{"required": "forms", "email": "project"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got := f.For("internal/other"); !reflect.DeepEqual(got, f.Settings) {
+		t.Errorf("got %+v for a package without overrides, want the project settings", got)
+	}
+}
@@ -0,0 +1,255 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Unified returns a unified diff turning a into b, or an empty string when
+// they are identical
+func Unified(oldName, newName, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	ops := lineOps(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n", oldName)
+	fmt.Fprintf(&out, "+++ %s\n", newName)
+
+	for _, h := range hunks(ops) {
+		writeHunk(&out, ops, h)
+	}
+
+	return out.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineOps computes a shortest edit script turning a into b with Myers'
+// algorithm in linear space, so large files with few changes stay cheap
+func lineOps(a, b []string) []op {
+	d := differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+	return d.ops
+}
+
+type differ struct {
+	a, b []string
+	ops  []op
+}
+
+// compare appends the ops turning a[alo:ahi] into b[blo:bhi]
+func (d *differ) compare(alo, ahi, blo, bhi int) {
+	for alo < ahi && blo < bhi && d.a[alo] == d.b[blo] {
+		d.ops = append(d.ops, op{opEqual, d.a[alo]})
+		alo++
+		blo++
+	}
+
+	suffix := ahi
+	for ahi > alo && bhi > blo && d.a[ahi-1] == d.b[bhi-1] {
+		ahi--
+		bhi--
+	}
+
+	switch {
+	case alo == ahi:
+		for _, line := range d.b[blo:bhi] {
+			d.ops = append(d.ops, op{opInsert, line})
+		}
+	case blo == bhi:
+		for _, line := range d.a[alo:ahi] {
+			d.ops = append(d.ops, op{opDelete, line})
+		}
+	default:
+		x, y, u, v := d.middleSnake(alo, ahi, blo, bhi)
+		d.compare(alo, x, blo, y)
+		for _, line := range d.a[x:u] {
+			d.ops = append(d.ops, op{opEqual, line})
+		}
+		d.compare(u, ahi, v, bhi)
+	}
+
+	for _, line := range d.a[ahi:suffix] {
+		d.ops = append(d.ops, op{opEqual, line})
+	}
+}
+
+// middleSnake finds the snake, a run of equal lines from (x, y) to (u, v),
+// in the middle of a shortest edit script for a[alo:ahi] and b[blo:bhi], by
+// searching forwards from the start and backwards from the end at once
+func (d *differ) middleSnake(alo, ahi, blo, bhi int) (x, y, u, v int) {
+	n, m := ahi-alo, bhi-blo
+	limit := (n + m + 1) / 2
+	delta := n - m
+	odd := delta%2 != 0
+
+	// forward[k] is the furthest x reached on diagonal k = x - y, and
+	// backward[c] the furthest distance from the end on reversed diagonal c,
+	// both offset so negative diagonals index from zero
+	offset := limit + 1
+	forward := make([]int, 2*limit+3)
+	backward := make([]int, 2*limit+3)
+
+	for step := 0; step <= limit; step++ {
+		for k := -step; k <= step; k += 2 {
+			var fx int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				fx = forward[offset+k+1]
+			} else {
+				fx = forward[offset+k-1] + 1
+			}
+			fy := fx - k
+			sx, sy := fx, fy
+			for fx < n && fy < m && d.a[alo+fx] == d.b[blo+fy] {
+				fx++
+				fy++
+			}
+			forward[offset+k] = fx
+
+			if c := delta - k; odd && c >= -(step-1) && c <= step-1 && fx+backward[offset+c] >= n {
+				return alo + sx, blo + sy, alo + fx, blo + fy
+			}
+		}
+
+		for c := -step; c <= step; c += 2 {
+			var rx int
+			if c == -step || (c != step && backward[offset+c-1] < backward[offset+c+1]) {
+				rx = backward[offset+c+1]
+			} else {
+				rx = backward[offset+c-1] + 1
+			}
+			ry := rx - c
+			sx, sy := rx, ry
+			for rx < n && ry < m && d.a[ahi-rx-1] == d.b[bhi-ry-1] {
+				rx++
+				ry++
+			}
+			backward[offset+c] = rx
+
+			if k := delta - c; !odd && k >= -step && k <= step && forward[offset+k]+rx >= n {
+				return ahi - rx, bhi - ry, ahi - sx, bhi - sy
+			}
+		}
+	}
+
+	panic("diff: no middle snake")
+}
+
+// hunk is a half-open range of ops to print together
+type hunk struct {
+	start, end int
+}
+
+func hunks(ops []op) []hunk {
+	var result []hunk
+
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == opEqual {
+			continue
+		}
+
+		start := max(i-contextLines, 0)
+		end := i + 1
+
+		// Extend the hunk while the next change is close enough to share context
+		for end < len(ops) {
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next == len(ops) || next-end > 2*contextLines {
+				break
+			}
+			end = next + 1
+		}
+		end = min(end+contextLines, len(ops))
+
+		if n := len(result); n > 0 && result[n-1].end >= start {
+			result[n-1].end = end
+		} else {
+			result = append(result, hunk{start, end})
+		}
+		i = end - 1
+	}
+
+	return result
+}
+
+func writeHunk(out *strings.Builder, ops []op, h hunk) {
+	// Line numbers in a and b where the hunk begins
+	aLine, bLine := 1, 1
+	for _, o := range ops[:h.start] {
+		if o.kind != opInsert {
+			aLine++
+		}
+		if o.kind != opDelete {
+			bLine++
+		}
+	}
+
+	var aCount, bCount int
+	for _, o := range ops[h.start:h.end] {
+		if o.kind != opInsert {
+			aCount++
+		}
+		if o.kind != opDelete {
+			bCount++
+		}
+	}
+
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+
+	for _, o := range ops[h.start:h.end] {
+		switch o.kind {
+		case opEqual:
+			out.WriteString(" ")
+		case opDelete:
+			out.WriteString("-")
+		case opInsert:
+			out.WriteString("+")
+		}
+		out.WriteString(o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty range refers to the line before the insertion point
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
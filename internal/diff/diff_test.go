@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// apply rebuilds both sides of an edit script, so tests can check the ops
+// turn a into b
+func apply(ops []op) (a, b []string) {
+	for _, o := range ops {
+		if o.kind != opInsert {
+			a = append(a, o.line)
+		}
+		if o.kind != opDelete {
+			b = append(b, o.line)
+		}
+	}
+	return a, b
+}
+
+// lcsLength is the textbook quadratic longest common subsequence, used to
+// check lineOps finds a shortest edit script
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestLineOps_Shortest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a\n", "b\n", "c\n", "d\n"}
+	random := func() []string {
+		lines := make([]string, rng.Intn(12))
+		for i := range lines {
+			lines[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return lines
+	}
+
+	for i := 0; i < 2000; i++ {
+		a, b := random(), random()
+		ops := lineOps(a, b)
+
+		gotA, gotB := apply(ops)
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("ops for %q -> %q rebuild %q -> %q", a, b, gotA, gotB)
+		}
+
+		edits := 0
+		for _, o := range ops {
+			if o.kind != opEqual {
+				edits++
+			}
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); edits != want {
+			t.Fatalf("ops for %q -> %q make %d edits, want %d", a, b, edits, want)
+		}
+	}
+}
+
+func TestUnified_LargeFile(t *testing.T) {
+	var a strings.Builder
+	for i := range 200000 {
+		fmt.Fprintf(&a, "line %d\n", i)
+	}
+	b := strings.Replace(a.String(), "line 2\n", "line two\n", 1)
+	b = strings.Replace(b, "line 199990\n", "line 199990\nextra\n", 1)
+
+	start := time.Now()
+	got := Unified("a/x.go", "b/x.go", a.String(), b)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("diff took %v", elapsed)
+	}
+
+	want := `--- a/x.go
++++ b/x.go
+@@ -1,6 +1,6 @@
+ line 0
+ line 1
+-line 2
++line two
+ line 3
+ line 4
+ line 5
+@@ -199989,6 +199989,7 @@
+ line 199988
+ line 199989
+ line 199990
++extra
+ line 199991
+ line 199992
+ line 199993
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	lockPoll  = 50 * time.Millisecond
+	lockWait  = 2 * time.Minute
+	lockStale = 5 * time.Minute // A lock this old was left by a run that crashed
+)
+
+// Lock is an advisory lock held by creating a file, so concurrent runs
+// writing to the same directory take turns
+type Lock struct {
+	path string
+}
+
+// Acquire creates the lock file at path, waiting while another process
+// holds it. A lock file older than lockStale is taken over.
+func Acquire(path string) (*Lock, error) {
+	deadline := time.Now().Add(lockWait)
+
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			return &Lock{path: path}, f.Close()
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(path)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s", path)
+		}
+		time.Sleep(lockPoll)
+	}
+}
+
+// Release removes the lock file
+func (l *Lock) Release() error {
+	return os.Remove(l.path)
+}
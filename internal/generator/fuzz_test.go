@@ -0,0 +1,91 @@
+package generator_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/richardbowden/valforge/internal/generator"
+	vfparser "github.com/richardbowden/valforge/internal/parser"
+	"github.com/richardbowden/valforge/internal/rules"
+	"github.com/richardbowden/valforge/internal/typechecker"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// FuzzGenerate feeds arbitrary tags through the parser, type checker and
+// generator. Tags the type checker accepts must generate code that parses
+// and declares nothing but Validate functions, so no rule parameter or
+// message can inject code.
+func FuzzGenerate(f *testing.F) {
+	f.Add("gt=0", "", "count")
+	f.Add("gt=0 || true", "", "count")
+	f.Add(`contains="),endswith=\`, `contains=say "hi" %s`, "name")
+	f.Add("eqfield=Name) || (true", "", "name")
+	f.Add("minlen=1 {\n}\nfunc evil() {", "", "name")
+	f.Add("required@create,omitempty@update,email", "required=}{", `na"me`)
+	f.Add("alpha,lowercase,startswith=`", "", "na\\me")
+
+	f.Fuzz(func(t *testing.T, validate, vmsg, jsonName string) {
+		tag := fmt.Sprintf("validate:%s vmsg:%s json:%s",
+			strconv.Quote(validate), strconv.Quote(vmsg), strconv.Quote(jsonName))
+
+		var src strings.Builder
+		src.WriteString("package fuzz\n\ntype Target struct {\n")
+		for _, field := range []string{"Name string", "Other string", "Count int", "Flag bool", "Opt *bool"} {
+			fmt.Fprintf(&src, "\t%s %s\n", field, strconv.Quote(tag))
+		}
+		src.WriteString("}\n")
+
+		path := filepath.Join(t.TempDir(), "target.go")
+		if err := os.WriteFile(path, []byte(src.String()), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		config := vtypes.GenerationConfig{ModuleName: "example.com/fuzz", OutputFile: "target_validation.gen.go"}
+
+		p := vfparser.New(config)
+		structs, packageName, err := p.ParseFile(path)
+		if err != nil || len(p.Errors()) > 0 {
+			return
+		}
+		config.PackageName = packageName
+
+		registry := rules.NewRegistry()
+		rules.RegisterBuiltins(registry, config)
+
+		tc := typechecker.New(registry)
+		for _, s := range structs {
+			if len(tc.CheckStruct(s)) > 0 || len(tc.CheckGroups(s, nil)) > 0 {
+				return
+			}
+		}
+
+		out, err := generator.New(registry, config).Generate(structs)
+		if err != nil {
+			t.Fatalf("accepted tag %q failed to generate: %v", tag, err)
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), "out.go", out, 0)
+		if err != nil {
+			t.Fatalf("accepted tag %q generated invalid code: %v\n%s", tag, err, out)
+		}
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Tok != token.IMPORT {
+					t.Fatalf("tag %q generated a %s declaration\n%s", tag, d.Tok, out)
+				}
+			case *ast.FuncDecl:
+				if !strings.HasPrefix(d.Name.Name, "Validate") {
+					t.Fatalf("tag %q generated func %s\n%s", tag, d.Name.Name, out)
+				}
+			}
+		}
+	})
+}
@@ -2,10 +2,14 @@ package generator
 
 import (
 	"bytes"
+	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/genfile"
 	"github.com/richardbowden/valforge/internal/modulegen"
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
@@ -27,16 +31,11 @@ type RuleRegistry interface {
 }
 
 func New(registry RuleRegistry, config vtypes.GenerationConfig) *Generator {
-	valforgePkgName := config.ValforgePackage
-	if valforgePkgName == "" {
-		valforgePkgName = "valgen"
-	}
-
 	return &Generator{
 		registry:    registry,
 		config:      config,
 		moduleGen:   modulegen.NewGenerator(config),
-		moduleAlias: valforgePkgName,
+		moduleAlias: vtypes.SupportAlias,
 		genTime:     time.Now(),
 	}
 }
@@ -44,8 +43,13 @@ func New(registry RuleRegistry, config vtypes.GenerationConfig) *Generator {
 func (g *Generator) Generate(structs []vtypes.ValidationStruct) (string, error) {
 	cb := builder.NewCodeBuilder()
 
+	supportImport, err := g.moduleGen.GetImportPath()
+	if err != nil {
+		return "", err
+	}
+
 	g.generateHeader(cb)
-	g.generateImports(cb, structs)
+	g.generateImports(cb, structs, supportImport)
 
 	for _, s := range structs {
 		if err := g.generateValidationMethod(cb, s); err != nil {
@@ -53,81 +57,187 @@ func (g *Generator) Generate(structs []vtypes.ValidationStruct) (string, error)
 		}
 	}
 
-	return cb.String(), nil
+	out, err := cb.Format()
+	if err != nil {
+		return "", vtypes.InternalError(g.config.OutputFile, err)
+	}
+	return out, nil
 }
 
 func (g *Generator) getGenTime() string {
 	var tzBuffer bytes.Buffer
-	tzBuffer.WriteString("Generated at: ")
+	tzBuffer.WriteString(genfile.TimePrefix)
 	tzBuffer.WriteString(g.genTime.Format("2006-01-02 15:04:05 -0700 MST"))
 
 	return tzBuffer.String()
 }
 
 func (g *Generator) generateHeader(cb *builder.CodeBuilder) {
-	cb.Writeln("// Code generated by valforge. DO NOT EDIT.")
-	cb.Printf("// %s", g.getGenTime())
+	cb.Writeln(genfile.Header + ". DO NOT EDIT.")
+	cb.Writeln(g.getGenTime())
 	cb.Printf("// Version: %s", g.config.Version)
 	cb.Newline()
-	cb.Printf("package %s", g.config.PackageName)
+	cb.Printf("package %s", cb.Ident(g.outputPackage()))
 	cb.Newline()
 }
 
-func (g *Generator) generateImports(cb *builder.CodeBuilder, structs []vtypes.ValidationStruct) {
+func (g *Generator) outputPackage() string {
+	if g.config.OutputPackage != "" {
+		return g.config.OutputPackage
+	}
+	return g.config.PackageName
+}
+
+// standalone reports whether functions are generated in a package of their
+// own, importing the validated types
+func (g *Generator) standalone() bool {
+	return g.config.StandaloneDir != ""
+}
+
+func (g *Generator) generateImports(cb *builder.CodeBuilder, structs []vtypes.ValidationStruct, supportImport string) {
 	var allFields []vtypes.ValidationField
 	for _, s := range structs {
 		allFields = append(allFields, s.Fields...)
 	}
 
 	imports := g.registry.GetRequiredImports(allFields)
+	if g.config.Context {
+		imports = append(imports, "context")
+	}
+	for _, s := range structs {
+		if len(structGroups(s)) > 0 {
+			imports = append(imports, "slices")
+			break
+		}
+	}
 
 	cb.Writeln("import (")
 	cb.Indent()
 
 	// Standard library imports
 	for _, imp := range imports {
-		cb.Printf(`"%s"`, imp)
+		cb.Writeln(cb.Quote(imp))
 	}
 	cb.Newline()
 
 	// Error package import
-	errorImportPath := g.moduleGen.GetImportPath()
-	cb.Printf(`%s "%s"`, g.moduleAlias, errorImportPath)
+	cb.Printf("%s %s", g.moduleAlias, cb.Quote(supportImport))
+
+	// Validated package import
+	if g.standalone() {
+		cb.Printf("%s %s", cb.Ident(g.config.PackageName), cb.Quote(g.config.TargetImportPath))
+	}
 
 	cb.Dedent()
 	cb.Writeln(")")
 	cb.Newline()
 }
 
+// entry is one generated entry point of a struct. The one doing the work
+// takes every parameter in use and the others call it.
+type entry struct {
+	suffix string // Appended to the Validate name, e.g. "Context"
+	ctx    bool   // Takes ctx context.Context
+	groups bool   // Takes groups ...string
+}
+
 func (g *Generator) generateValidationMethod(cb *builder.CodeBuilder, s vtypes.ValidationStruct) error {
-	cb.Printf("func (v %s) Validate() error {", s.Name)
+	groups := structGroups(s)
+	grouped := len(groups) > 0
+
+	body := entry{ctx: g.config.Context, groups: grouped}
+	if grouped {
+		body.suffix += "Groups"
+	}
+	if g.config.Context {
+		body.suffix += "Context"
+	}
+
+	// Wrappers calling the body with a background context and no groups
+	if grouped || g.config.Context {
+		g.generateWrapper(cb, s, entry{}, body, "")
+	}
+	if grouped && g.config.Context {
+		g.generateWrapper(cb, s, entry{suffix: "Context", ctx: true}, body, "")
+		g.generateWrapper(cb, s, entry{suffix: "Groups", groups: true}, body, "groups...")
+	}
+	for _, group := range groups {
+		g.generateWrapper(cb, s, entry{suffix: vtypes.GroupMethodName(group)}, body, cb.Quote(group))
+	}
+
+	cb.Writeln(g.signature(cb, s, body))
 	cb.Indent()
 
-	cb.Printf("verr := %s.NewValidationError(\"%s\")", g.moduleAlias, s.Name)
+	if g.config.Context {
+		cb.Writeln("if err := ctx.Err(); err != nil {")
+		cb.Indent()
+		cb.Writeln("return err")
+		cb.Dedent()
+		cb.Writeln("}")
+		cb.Newline()
+	}
+
+	cb.Printf("verr := %s.NewValidationError(%s)", g.moduleAlias, cb.Quote(s.Name))
 	cb.Newline()
 
 	rules := g.registry.GetAllForGeneration()
 
 	for _, field := range s.Fields {
-		var applicableRules []interface {
-			Generate(*builder.CodeBuilder, vtypes.ValidationField, string) error
-			Priority() int
-		}
+		field = g.withMessages(field)
 
+		// Each rule is generated on its own so it can be limited to groups
+		var names []string
 		for ruleName := range field.Rules {
-			if rule, exists := rules[ruleName]; exists {
-				applicableRules = append(applicableRules, rule)
+			if _, exists := rules[ruleName]; exists {
+				names = append(names, ruleName)
 			}
 		}
 
-		sort.Slice(applicableRules, func(i, j int) bool {
-			return applicableRules[i].Priority() < applicableRules[j].Priority()
+		sort.Slice(names, func(i, j int) bool {
+			pi, pj := rules[names[i]].Priority(), rules[names[j]].Priority()
+			if pi != pj {
+				return pi < pj
+			}
+			return names[i] < names[j]
 		})
 
-		for _, rule := range applicableRules {
-			if err := rule.Generate(cb, field, s.Name); err != nil {
+		// omitempty skips every rule of the field while it holds its zero value
+		omit := field.OmitEmpty && len(names) > 0
+		if omit {
+			cond, _ := field.Type.NonZero("v." + field.Name)
+			if len(field.OmitIn) > 0 {
+				cond = fmt.Sprintf("%s || !(%s)", cond, groupCondition(cb, field.OmitIn))
+			}
+			cb.Printf("if %s {", cond)
+			cb.Indent()
+		}
+
+		for _, ruleName := range names {
+			single := field
+			single.Rules = map[string]string{ruleName: field.Rules[ruleName]}
+
+			guard := field.Groups[ruleName]
+			if len(guard) > 0 {
+				cb.Printf("if %s {", groupCondition(cb, guard))
+				cb.Indent()
+			}
+
+			if err := rules[ruleName].Generate(cb, single, s.Name); err != nil {
 				return err
 			}
+			if err := cb.Err(); err != nil {
+				return vtypes.InternalError(g.config.OutputFile, fmt.Errorf("%s.%s: rule '%s': %w", s.Name, field.Name, ruleName, err))
+			}
+
+			if len(guard) > 0 {
+				cb.Dedent()
+				cb.Writeln("}")
+			}
+		}
+
+		if omit {
+			cb.Dedent()
+			cb.Writeln("}")
 		}
 		cb.Newline()
 	}
@@ -146,3 +256,109 @@ func (g *Generator) generateValidationMethod(cb *builder.CodeBuilder, s vtypes.V
 
 	return nil
 }
+
+// generateWrapper emits entry point e calling body, passing a background
+// context when e has none and groupsArg as the groups
+func (g *Generator) generateWrapper(cb *builder.CodeBuilder, s vtypes.ValidationStruct, e, body entry, groupsArg string) {
+	var args []string
+	if body.ctx {
+		if e.ctx {
+			args = append(args, "ctx")
+		} else {
+			args = append(args, "context.Background()")
+		}
+	}
+	if g.standalone() {
+		args = append(args, "v")
+	}
+	if body.groups && groupsArg != "" {
+		args = append(args, groupsArg)
+	}
+
+	callee := "v.Validate" + body.suffix
+	if g.standalone() {
+		callee = "Validate" + s.Name + body.suffix
+	}
+
+	cb.Writeln(g.signature(cb, s, e))
+	cb.Indent()
+	cb.Printf("return %s(%s)", callee, strings.Join(args, ", "))
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Newline()
+}
+
+// signature opens entry point e of s: a method, or a ValidateT function in
+// standalone mode, as Go does not allow methods on types of other packages
+func (g *Generator) signature(cb *builder.CodeBuilder, s vtypes.ValidationStruct, e entry) string {
+	name := cb.Ident(s.Name)
+
+	var params []string
+	if e.ctx {
+		params = append(params, "ctx context.Context")
+	}
+	if g.standalone() {
+		params = append(params, fmt.Sprintf("v *%s.%s", cb.Ident(g.config.PackageName), name))
+	}
+	if e.groups {
+		params = append(params, "groups ...string")
+	}
+
+	if g.standalone() {
+		return fmt.Sprintf("func Validate%s%s(%s) error {", name, e.suffix, strings.Join(params, ", "))
+	}
+
+	receiver := name
+	if s.PointerReceiver {
+		receiver = "*" + name
+	}
+	return fmt.Sprintf("func (v %s) Validate%s(%s) error {", receiver, e.suffix, strings.Join(params, ", "))
+}
+
+// structGroups returns the groups any rule of s is limited to, sorted
+func structGroups(s vtypes.ValidationStruct) []string {
+	var groups []string
+	add := func(names []string) {
+		for _, group := range names {
+			if !slices.Contains(groups, group) {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	for _, field := range s.Fields {
+		for _, ruleGroups := range field.Groups {
+			add(ruleGroups)
+		}
+		add(field.OmitIn)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// groupCondition is true when any of groups is being validated
+func groupCondition(cb *builder.CodeBuilder, groups []string) string {
+	conds := make([]string, len(groups))
+	for i, group := range groups {
+		conds[i] = fmt.Sprintf("slices.Contains(groups, %s)", cb.Quote(group))
+	}
+	return strings.Join(conds, " || ")
+}
+
+// withMessages layers the configured message templates under the field's own overrides
+func (g *Generator) withMessages(field vtypes.ValidationField) vtypes.ValidationField {
+	if len(g.config.Messages) == 0 {
+		return field
+	}
+
+	merged := make(map[string]string, len(g.config.Messages)+len(field.Messages))
+	for code, tmpl := range g.config.Messages {
+		merged[code] = tmpl
+	}
+	for code, tmpl := range field.Messages {
+		merged[code] = tmpl
+	}
+	field.Messages = merged
+
+	return field
+}
@@ -0,0 +1,43 @@
+// Package genfile recognises and compares the files valforge generates
+package genfile
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// Header starts every file valforge writes
+const Header = "// Code generated by valforge"
+
+// TimePrefix starts the header line carrying the generation timestamp
+const TimePrefix = "// Generated at: "
+
+// IsGenerated reports whether the file at path was written by valforge
+func IsGenerated(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	head := make([]byte, len(Header))
+	if _, err := io.ReadFull(f, head); err != nil {
+		return false
+	}
+	return bytes.Equal(head, []byte(Header))
+}
+
+// Normalize strips the generation timestamp from generated source, so two
+// runs over the same input compare equal
+func Normalize(src string) string {
+	lines := strings.SplitAfter(src, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, TimePrefix) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "")
+}
@@ -0,0 +1,46 @@
+package genfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsGenerated(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"gen.go":     Header + ". DO NOT EDIT.\npackage app\n",
+		"other.go":   "// Code generated by stringer. DO NOT EDIT.\npackage app\n",
+		"hand.go":    "package app\n\n" + Header + "\n",
+		"short.go":   "//",
+		"missing.go": "",
+	}
+	want := map[string]bool{"gen.go": true}
+
+	for name, src := range files {
+		if name == "missing.go" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name := range files {
+		if got := IsGenerated(filepath.Join(dir, name)); got != want[name] {
+			t.Errorf("IsGenerated(%s) = %v, want %v", name, got, want[name])
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	a := Header + ". DO NOT EDIT.\n" + TimePrefix + "2026-01-02 15:04:05 +0000 UTC\npackage app\n"
+	b := Header + ". DO NOT EDIT.\n" + TimePrefix + "2026-10-19 08:00:00 +0200 CEST\npackage app\n"
+
+	if Normalize(a) != Normalize(b) {
+		t.Errorf("sources differing only in the timestamp compare unequal:\n%s\n%s", Normalize(a), Normalize(b))
+	}
+	if got, want := Normalize(a), Header+". DO NOT EDIT.\npackage app\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+package modulegen
+
+const diveCode = `
+// Dive validates v, a pointer to a nested value, with its Validate method
+// and adds the field errors to verr under prefix. Values without a Validate
+// method are valid.
+func Dive(verr *ValidationError, prefix string, v any) {
+	if n, ok := v.(interface{ Validate() error }); ok {
+		verr.Nest(prefix, n.Validate())
+	}
+}
+
+// DiveContext is Dive passing ctx to the ValidateContext method of v when it
+// has one. It returns ctx.Err() once ctx is done, before or after validating
+// v, so the caller stops rather than recording the cancellation against v.
+func DiveContext(ctx context.Context, verr *ValidationError, prefix string, v any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var err error
+	switch n := v.(type) {
+	case interface{ ValidateContext(context.Context) error }:
+		err = n.ValidateContext(ctx)
+	case interface{ Validate() error }:
+		err = n.Validate()
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	verr.Nest(prefix, err)
+	return nil
+}
+
+// IndexPath returns the path of a slice or map element, e.g. items[2]
+func IndexPath(prefix string, key any) string {
+	return fmt.Sprintf("%s[%v]", prefix, key)
+}
+`
@@ -2,11 +2,16 @@ package modulegen
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/genfile"
 	"github.com/richardbowden/valforge/internal/project"
+	"github.com/richardbowden/valforge/internal/rules"
 	"github.com/richardbowden/valforge/internal/vfcontext"
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
@@ -23,144 +28,128 @@ func NewGenerator(config vtypes.GenerationConfig) *Generator {
 	return g
 }
 
+// EnsurePackages renders the supporting package files into ctx.SupportFiles.
+// Nothing is written to disk here so that check mode can compare the result
+// against the files already present.
 func (g *Generator) EnsurePackages(ctx *vfcontext.Context) error {
-	if err := os.MkdirAll(g.packagePath, 0755); err != nil {
-		return fmt.Errorf("failed to create error package directory: %w", err)
-	}
-
-	err := g.ensureErrorPackage()
+	err := g.ensureErrorPackage(ctx)
 
 	if err != nil {
 		return fmt.Errorf("failed to create errors.go: %w", err)
 
 	}
 
-	err = g.ensureEmailValidation(ctx)
+	err = g.ensureHelpers(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create email.go: %w", err)
-
-	}
-	return nil
-}
-
-func (g *Generator) ensureEmailValidation(ctx *vfcontext.Context) error {
-	packagePath := g.getValforgePackagePath()
-	emailValidationFile := filepath.Join(packagePath, "emailvalidation.gen.go")
-
-	cb := builder.NewCodeBuilder()
-
-	emailCode := `
-func ValidateEmail(email string) error {
-	// Check if email is empty
-	if email == "" {
-		return errors.New("email cannot be empty")
-	}
-
-	// Check for spaces
-	if strings.Contains(email, " ") {
-		return errors.New("email cannot contain spaces")
-	}
-
-	// Check for exactly one @ symbol
-	atIndex := strings.Index(email, "@")
-	if atIndex == -1 {
-		return errors.New("email must contain an @ symbol")
-	}
-
-	if atIndex != strings.LastIndex(email, "@") {
-		return errors.New("email cannot contain multiple @ symbols")
+		return fmt.Errorf("failed to create validation helpers: %w", err)
 	}
 
-	// Split into local and domain parts
-	localPart := email[:atIndex]
-	domainPart := email[atIndex+1:]
-
-	// Check local part isn't empty
-	if localPart == "" {
-		return errors.New("email must have a local part before the @ symbol")
-	}
-
-	// Check domain part isn't empty
-	if domainPart == "" {
-		return errors.New("email must have a domain part after the @ symbol")
+	err = g.ensureTranslations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create translate.go: %w", err)
 	}
 
-	// Check domain contains at least one dot
-	if !strings.Contains(domainPart, ".") {
-		return errors.New("domain must contain at least one dot")
+	err = g.ensureProblemDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create problem.go: %w", err)
 	}
 
-	// Check that dot isn't first or last character in domain
-	if strings.HasPrefix(domainPart, ".") {
-		return errors.New("domain cannot start with a dot")
+	err = g.ensureGRPC(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create grpc.go: %w", err)
 	}
+	return nil
+}
 
-	if strings.HasSuffix(domainPart, ".") {
-		return errors.New("domain cannot end with a dot")
-	}
+func (g *Generator) ensureErrorPackage(ctx *vfcontext.Context) error {
+	errorsFile := filepath.Join(g.packagePath, "errors.gen.go")
 
-	// Check there's content after the last dot (TLD)
-	lastDotIndex := strings.LastIndex(domainPart, ".")
-	if lastDotIndex == len(domainPart)-1 {
-		return errors.New("domain must have a TLD after the last dot")
-	}
+	cb := g.newFile("encoding/json", "fmt", "strings")
+	g.generateErrorPackage(cb, builtinRuleNames(g.config))
 
-	return nil
+	return g.addFile(ctx, errorsFile, cb)
 }
-`
 
-	cb.Writeln("// Code generated by valforge. DO NOT EDIT.")
+// newFile starts a support package file with the generated header, the
+// package clause and imports, so every file valforge writes has the same
+// preamble
+func (g *Generator) newFile(imports ...string) *builder.CodeBuilder {
+	cb := builder.NewCodeBuilder()
+
+	cb.Writeln(genfile.Header + ". DO NOT EDIT.")
 	cb.Printf("// Version: %s", g.config.Version)
-	cb.Printf("package %s", g.getValforgePackageName())
+	cb.Printf("package %s", cb.Ident(g.getValforgePackageName()))
 	cb.Newline()
 
-	cb.Writeln("import (")
-	cb.Indent()
-	cb.Writeln(`"errors"`)
-	cb.Writeln(`"strings"`)
-	cb.Dedent()
-	cb.Writeln(")")
-	cb.Writeln(emailCode)
-
-	if err := os.WriteFile(emailValidationFile, []byte(cb.String()), 0644); err != nil {
-		return fmt.Errorf("failed to write email file: %w", err)
+	if len(imports) > 0 {
+		cb.Writeln("import (")
+		cb.Indent()
+		for _, imp := range imports {
+			cb.Writeln(cb.Quote(imp))
+		}
+		cb.Dedent()
+		cb.Writeln(")")
+		cb.Newline()
 	}
-
-	return nil
+	return cb
 }
 
-func (g *Generator) ensureErrorPackage() error {
-	errorsFile := filepath.Join(g.packagePath, "errors.gen.go")
-
-	cb := builder.NewCodeBuilder()
-	g.generateErrorPackage(cb)
-
-	if err := os.WriteFile(errorsFile, []byte(cb.String()), 0644); err != nil {
-		return fmt.Errorf("failed to write errors file: %w", err)
+// addFile formats the built code and queues it for writing
+func (g *Generator) addFile(ctx *vfcontext.Context, path string, cb *builder.CodeBuilder) error {
+	content, err := cb.Format()
+	if err != nil {
+		return vtypes.InternalError(path, err)
 	}
 
-	fmt.Printf("✓ Generated validation errors in %s\n", errorsFile)
+	ctx.SupportFiles = append(ctx.SupportFiles, vfcontext.File{
+		Path:    path,
+		Content: content,
+	})
+
 	return nil
 }
 
-func (g *Generator) GetImportPath() string {
-	moduleName := g.config.ModuleName
+// GetImportPath returns the import path of the support package, from its
+// directory relative to the module root
+func (g *Generator) GetImportPath() (string, error) {
+	root, moduleName := g.config.ProjectRoot, g.config.ModuleName
 	if moduleName == "" {
 		// Try to find module name if not set
-		if g.config.ProjectRoot != "" {
-			_, foundModule, err := project.FindProjectRoot(g.config.ProjectRoot)
-			if err == nil && foundModule != "" {
-				moduleName = foundModule
-			}
+		foundRoot, foundModule, err := project.FindProjectRoot(root)
+		if err == nil && foundModule != "" {
+			root, moduleName = foundRoot, foundModule
 		}
 	}
 
-	if moduleName != "" {
-		return fmt.Sprintf("%s/internal/%s", moduleName, g.getValforgePackageName())
+	rel, err := relativePath(root, g.packagePath)
+	if err != nil {
+		return "", err
+	}
+
+	if moduleName == "" {
+		// Fallback: still use full path format - user should set module name
+		return "./" + rel, nil
+	}
+	return moduleName + "/" + rel, nil
+}
+
+// relativePath returns dir relative to root with forward slashes, failing
+// when dir is outside root and so cannot be imported from it
+func relativePath(root, dir string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
 	}
 
-	// Fallback: still use full path format - user should set module name
-	return fmt.Sprintf("./internal/%s", g.getValforgePackageName())
+	rel, err := filepath.Rel(absRoot, absDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("support package %s is outside the project %s", dir, root)
+	}
+	return filepath.ToSlash(rel), nil
 }
 
 func (g *Generator) getValforgePackagePath() string {
@@ -177,27 +166,23 @@ func (g *Generator) getValforgePackageName() string {
 	return "valgen"
 }
 
-func (g *Generator) generateErrorPackage(cb *builder.CodeBuilder) {
-	cb.Writeln("// Code generated by valforge. DO NOT EDIT.")
-	cb.Printf("// Version: %s", g.config.Version)
-	cb.Printf("package %s", g.getValforgePackageName())
-	cb.Newline()
-
-	cb.Writeln("import (")
-	cb.Indent()
-	cb.Writeln(`"encoding/json"`)
-	cb.Writeln(`"fmt"`)
-	cb.Writeln(`"strings"`)
-	cb.Dedent()
-	cb.Writeln(")")
-	cb.Newline()
-
+func (g *Generator) generateErrorPackage(cb *builder.CodeBuilder, ruleNames []string) {
 	// FieldError type
 	cb.Writeln("type FieldError struct {")
 	cb.Indent()
-	cb.Writeln(`Field   string      ` + "`json:\"field\"`")
-	cb.Writeln(`Message string      ` + "`json:\"message\"`")
-	cb.Writeln(`Value   interface{} ` + "`json:\"value,omitempty\"`")
+	cb.Writeln(`Field   string            ` + "`json:\"field\"`")
+	cb.Writeln(`Path    string            ` + "`json:\"path,omitempty\"`")
+	cb.Writeln(`GoField string            ` + "`json:\"goField,omitempty\"`")
+	cb.Writeln(`Rule    string            ` + "`json:\"rule,omitempty\"`")
+	cb.Writeln(`Code    string            ` + "`json:\"code,omitempty\"`")
+	cb.Writeln(`Message string            ` + "`json:\"message\"`")
+	cb.Writeln(`Params  map[string]string ` + "`json:\"params,omitempty\"`")
+	cb.Writeln(`Value   interface{}       ` + "`json:\"value,omitempty\"`")
+	cb.Writeln("// Redacted is set instead of Value for sensitive fields")
+	cb.Writeln(`Redacted bool ` + "`json:\"redacted,omitempty\"`")
+	cb.Writeln("// Custom is set when Message comes from a vmsg tag, a //valforge:msg")
+	cb.Writeln("// directive or configured messages, which Localize keeps")
+	cb.Writeln(`Custom bool ` + "`json:\"custom,omitempty\"`")
 	cb.Dedent()
 	cb.Writeln("}")
 	cb.Newline()
@@ -209,6 +194,18 @@ func (g *Generator) generateErrorPackage(cb *builder.CodeBuilder) {
 	cb.Writeln("}")
 	cb.Newline()
 
+	cb.Writeln("// Is reports whether target is the sentinel for the failed rule or its")
+	cb.Writeln("// message code, so errors.Is(err, ErrRequired) finds required failures")
+	cb.Writeln("func (e FieldError) Is(target error) bool {")
+	cb.Indent()
+	cb.Writeln("re, ok := target.(*RuleError)")
+	cb.Writeln("return ok && (re.Rule == e.Rule || re.Rule == e.Code)")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Newline()
+
+	g.generateSentinels(cb, ruleNames)
+
 	// ValidationError type
 	cb.Writeln("type ValidationError struct {")
 	cb.Indent()
@@ -256,6 +253,47 @@ func (g *Generator) generateErrorPackage(cb *builder.CodeBuilder) {
 	cb.Writeln("}")
 	cb.Newline()
 
+	cb.Writeln("func (e *ValidationError) Add(fe FieldError) {")
+	cb.Indent()
+	cb.Writeln("if fe.Path == \"\" {")
+	cb.Indent()
+	cb.Writeln("fe.Path = fe.Field")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Writeln("e.Errors = append(e.Errors, fe)")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Newline()
+
+	cb.Writeln("// Nest adds the field errors from validating a nested value, with their")
+	cb.Writeln("// paths prefixed by prefix, e.g. \"address\". Other errors are recorded")
+	cb.Writeln("// against prefix itself.")
+	cb.Writeln("func (e *ValidationError) Nest(prefix string, err error) {")
+	cb.Indent()
+	cb.Writeln("if err == nil {")
+	cb.Indent()
+	cb.Writeln("return")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Newline()
+	cb.Writeln("nested, ok := err.(*ValidationError)")
+	cb.Writeln("if !ok {")
+	cb.Indent()
+	cb.Writeln("e.Add(FieldError{Field: prefix, Message: err.Error()})")
+	cb.Writeln("return")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Newline()
+	cb.Writeln("for _, fe := range nested.Errors {")
+	cb.Indent()
+	cb.Writeln("fe.Path = prefix + \".\" + fe.Path")
+	cb.Writeln("e.Errors = append(e.Errors, fe)")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Newline()
+
 	cb.Writeln("func (e *ValidationError) JSON() ([]byte, error) {")
 	cb.Indent()
 	cb.Writeln("return json.Marshal(e)")
@@ -263,6 +301,20 @@ func (g *Generator) generateErrorPackage(cb *builder.CodeBuilder) {
 	cb.Writeln("}")
 	cb.Newline()
 
+	cb.Writeln("// Unwrap returns the field errors, so errors.Is and errors.As look into them")
+	cb.Writeln("func (e *ValidationError) Unwrap() []error {")
+	cb.Indent()
+	cb.Writeln("errs := make([]error, len(e.Errors))")
+	cb.Writeln("for i, fe := range e.Errors {")
+	cb.Indent()
+	cb.Writeln("errs[i] = fe")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Writeln("return errs")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Newline()
+
 	cb.Writeln("func (e *ValidationError) HasField(field string) bool {")
 	cb.Indent()
 	cb.Writeln("for _, f := range e.Errors {")
@@ -290,3 +342,85 @@ func (g *Generator) generateErrorPackage(cb *builder.CodeBuilder) {
 	cb.Dedent()
 	cb.Writeln("}")
 }
+
+// generateSentinels emits a RuleError sentinel per registered rule, e.g.
+// ErrRequired for required
+func (g *Generator) generateSentinels(cb *builder.CodeBuilder, ruleNames []string) {
+	cb.Writeln("// RuleError is the sentinel type FieldError.Is matches against")
+	cb.Writeln("type RuleError struct {")
+	cb.Indent()
+	cb.Writeln("Rule string")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Newline()
+
+	cb.Writeln("func (e *RuleError) Error() string {")
+	cb.Indent()
+	cb.Writeln(`return fmt.Sprintf("validation rule %s failed", e.Rule)`)
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Newline()
+
+	if len(ruleNames) == 0 {
+		return
+	}
+
+	cb.Writeln("var (")
+	cb.Indent()
+	for _, name := range ruleNames {
+		cb.Printf("// %s matches field errors from the %s rule", sentinelName(name), name)
+		cb.Printf("%s = &RuleError{Rule: %s}", cb.Ident(sentinelName(name)), cb.Quote(name))
+	}
+	cb.Dedent()
+	cb.Writeln(")")
+	cb.Newline()
+}
+
+// builtinRuleNames returns every built-in rule name. errors.gen.go is shared
+// by the whole project, so its sentinels must not depend on the rules one
+// package restricts itself to.
+func builtinRuleNames(config vtypes.GenerationConfig) []string {
+	registry := rules.NewRegistry()
+	rules.RegisterBuiltins(registry, config)
+
+	// dive records the nested errors rather than failing itself
+	return slices.DeleteFunc(registry.RuleNames(), func(name string) bool {
+		return name == "dive"
+	})
+}
+
+// initialisms are rule name words spelled in capitals in sentinel names,
+// following Go naming style
+var initialisms = map[string]string{
+	"ascii": "ASCII",
+	"cidr":  "CIDR",
+	"ip":    "IP",
+	"ipv4":  "IPv4",
+	"ipv6":  "IPv6",
+	"mac":   "MAC",
+	"ulid":  "ULID",
+	"uri":   "URI",
+	"url":   "URL",
+	"uuid":  "UUID",
+}
+
+// sentinelName returns the exported sentinel name for a rule, e.g. ErrMinlen
+// for minlen and ErrIPv4 for ipv4
+func sentinelName(rule string) string {
+	words := strings.FieldsFunc(rule, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var sb strings.Builder
+	sb.WriteString("Err")
+	for _, word := range words {
+		if initialism, ok := initialisms[strings.ToLower(word)]; ok {
+			sb.WriteString(initialism)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(word)
+		sb.WriteRune(unicode.ToUpper(r))
+		sb.WriteString(word[size:])
+	}
+	return sb.String()
+}
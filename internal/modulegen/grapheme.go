@@ -0,0 +1,57 @@
+package modulegen
+
+const graphemeCode = `
+const zeroWidthJoiner = '\u200d'
+
+// GraphemeCount counts the user-perceived characters in s. It approximates
+// Unicode extended grapheme clusters: combining marks, variation selectors,
+// emoji modifiers and zero width joiner sequences join the preceding
+// character, a pair of regional indicators is one flag, and CRLF counts once.
+// Hangul syllables written as separate jamo count per jamo.
+func GraphemeCount(s string) int {
+	count := 0
+	prev := rune(-1)
+	regional := 0 // Regional indicators in the current run
+
+	for _, r := range s {
+		if !extendsCluster(prev, r, regional) {
+			count++
+		}
+
+		if isRegionalIndicator(r) {
+			regional++
+		} else {
+			regional = 0
+		}
+		prev = r
+	}
+
+	return count
+}
+
+func extendsCluster(prev, r rune, regional int) bool {
+	switch {
+	case prev < 0:
+		return false
+	case prev == '\r':
+		return r == '\n'
+	case unicode.IsControl(prev) || unicode.IsControl(r):
+		return false
+	case r == zeroWidthJoiner || prev == zeroWidthJoiner:
+		return true
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF: // Emoji skin tone modifiers
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // Tags spelling subdivision flags
+		return true
+	case isRegionalIndicator(r):
+		return regional%2 == 1
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+`
@@ -0,0 +1,49 @@
+package modulegen
+
+import (
+	"path/filepath"
+
+	"github.com/richardbowden/valforge/internal/vfcontext"
+)
+
+const grpcCode = `
+// GRPCStatus converts the error to an InvalidArgument status carrying a
+// BadRequest detail with a field violation per field error. It is picked
+// up by status.FromError and status.Code, so a *ValidationError can be
+// returned from a gRPC handler as is.
+func (e *ValidationError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+
+	br := &errdetails.BadRequest{}
+	for _, fe := range e.Errors {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fe.Path,
+			Description: fe.Message,
+		})
+	}
+
+	withDetails, err := st.WithDetails(br)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+`
+
+// grpcFileName is the file ensureGRPC emits, stale while gRPC is off
+const grpcFileName = "grpc.gen.go"
+
+// ensureGRPC emits the gRPC status conversion. It is opt-in because it
+// makes the support package depend on google.golang.org/grpc.
+func (g *Generator) ensureGRPC(ctx *vfcontext.Context) error {
+	if !g.config.GRPC {
+		return nil
+	}
+
+	grpcFile := filepath.Join(g.packagePath, grpcFileName)
+
+	cb := g.newFile("google.golang.org/genproto/googleapis/rpc/errdetails", "google.golang.org/grpc/codes", "google.golang.org/grpc/status")
+	cb.Writeln(grpcCode)
+
+	return g.addFile(ctx, grpcFile, cb)
+}
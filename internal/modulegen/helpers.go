@@ -0,0 +1,203 @@
+package modulegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardbowden/valforge/internal/fsutil"
+	"github.com/richardbowden/valforge/internal/genfile"
+	"github.com/richardbowden/valforge/internal/vfcontext"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// helper is a support package file emitted only when generated code calls
+// one of its funcs
+type helper struct {
+	option  vfcontext.PackageOptions
+	file    string
+	imports []string
+	funcs   []string
+	code    string
+}
+
+var helpers = []helper{
+	{vfcontext.EmailPO, "emailvalidation.gen.go", []string{"errors", "strings"}, []string{"ValidateEmail"}, emailCode},
+	{vfcontext.URLPO, "url.gen.go", []string{"net/url"}, []string{"IsURL", "IsURI"}, urlCode},
+	{vfcontext.HostnamePO, "hostname.gen.go", []string{"strings"}, []string{"IsHostname"}, hostnameCode},
+	{vfcontext.IPPO, "ip.gen.go", []string{"net/netip"}, []string{"IsIP", "IsIPv4", "IsIPv6", "IsCIDR"}, ipCode},
+	{vfcontext.MACPO, "mac.gen.go", []string{"net"}, []string{"IsMAC"}, macCode},
+	{vfcontext.PortPO, "port.gen.go", nil, []string{"IsPort"}, portCode},
+	{vfcontext.UUIDPO, "uuid.gen.go", nil, []string{"IsUUID"}, uuidCode},
+	{vfcontext.ULIDPO, "ulid.gen.go", nil, []string{"IsULID"}, ulidCode},
+	{vfcontext.GraphemePO, "grapheme.gen.go", []string{"unicode"}, []string{"GraphemeCount"}, graphemeCode},
+	{vfcontext.DivePO, "dive.gen.go", []string{"context", "fmt"}, []string{"Dive", "DiveContext", "IndexPath"}, diveCode},
+}
+
+// lockFile serializes runs writing the support package, see Lock
+const lockFile = ".valforge.lock"
+
+// ensureHelpers emits the helper files needed by the structs being generated
+// or by any other generated file in the project, and lists the rest as stale
+// for check mode
+func (g *Generator) ensureHelpers(ctx *vfcontext.Context) error {
+	used, err := g.projectOptions(ctx.Config.OutputFile)
+	if err != nil {
+		return err
+	}
+	ctx.PackageOptions |= used
+	ctx.StaleFiles = g.staleFiles(ctx.PackageOptions)
+
+	for _, h := range helpers {
+		if ctx.PackageOptions&h.option == 0 {
+			continue
+		}
+		path := filepath.Join(g.packagePath, h.file)
+
+		cb := g.newFile(h.imports...)
+		cb.Writeln(h.code)
+
+		if err := g.addFile(ctx, path, cb); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// staleFiles returns the generated support files no longer needed: helpers
+// outside options, and the gRPC conversion when it is turned off
+func (g *Generator) staleFiles(options vfcontext.PackageOptions) []string {
+	var candidates []string
+	for _, h := range helpers {
+		if options&h.option == 0 {
+			candidates = append(candidates, filepath.Join(g.packagePath, h.file))
+		}
+	}
+	if !g.config.GRPC {
+		candidates = append(candidates, filepath.Join(g.packagePath, grpcFileName))
+	}
+
+	var stale []string
+	for _, path := range candidates {
+		if genfile.IsGenerated(path) {
+			stale = append(stale, path)
+		}
+	}
+	return stale
+}
+
+// Lock takes the support package lock, held while files are written and
+// stale helpers removed. Runs for different packages, as under a parallel
+// go generate, then never remove a helper another run has just written.
+func (g *Generator) Lock() (*fsutil.Lock, error) {
+	if err := os.MkdirAll(g.packagePath, 0755); err != nil {
+		return nil, err
+	}
+	return fsutil.Acquire(filepath.Join(g.packagePath, lockFile))
+}
+
+// StaleFiles rescans the project and returns the support files nothing needs
+// any more. Call it holding Lock, after the run's own output is written, so
+// it sees every finished run's output.
+func (g *Generator) StaleFiles(ctx *vfcontext.Context) ([]string, error) {
+	used, err := g.projectOptions("")
+	if err != nil {
+		return nil, err
+	}
+	return g.staleFiles(ctx.PackageOptions | used), nil
+}
+
+// projectOptions returns the helpers called by the project's generated
+// files other than output, which is being regenerated, so generating one
+// package keeps the helpers other packages still use
+func (g *Generator) projectOptions(output string) (vfcontext.PackageOptions, error) {
+	byFunc := make(map[string]vfcontext.PackageOptions)
+	for _, h := range helpers {
+		for _, name := range h.funcs {
+			byFunc[name] = h.option
+		}
+	}
+
+	root := g.config.ProjectRoot
+	if root == "" {
+		root = "."
+	}
+	if output != "" {
+		output, _ = filepath.Abs(output)
+	}
+	support, _ := filepath.Abs(g.packagePath)
+
+	var options vfcontext.PackageOptions
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Generated files are not kept where valforge cannot read, so an
+			// unreadable directory does not stop generation
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		abs, _ := filepath.Abs(path)
+		if d.IsDir() {
+			if path != root && (abs == support || skipDir(path, d.Name())) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") || abs == output || !genfile.IsGenerated(path) {
+			return nil
+		}
+		for _, name := range supportCalls(path) {
+			options |= byFunc[name]
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan %s for generated files: %w", root, err)
+	}
+
+	return options, nil
+}
+
+// skipDir reports whether a directory holds no generated code of this
+// project: hidden, vendored, test data and JavaScript dependency
+// directories, and nested modules
+func skipDir(path, name string) bool {
+	switch {
+	case strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_"):
+		return true
+	case name == "vendor" || name == "testdata" || name == "node_modules":
+		return true
+	}
+	_, err := os.Stat(filepath.Join(path, "go.mod"))
+	return err == nil
+}
+
+// supportCalls returns the names the file at path selects from the support
+// package, e.g. ValidateEmail for valgen.ValidateEmail. Files that do not
+// parse contribute nothing.
+func supportCalls(path string) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Name == vtypes.SupportAlias {
+				names = append(names, sel.Sel.Name)
+			}
+		}
+		return true
+	})
+	return names
+}
@@ -0,0 +1,64 @@
+package modulegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardbowden/valforge/internal/genfile"
+	"github.com/richardbowden/valforge/internal/vfcontext"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// writeGenerated writes a generated file under root calling fn in the
+// support package
+func writeGenerated(t *testing.T, root, path, fn string) {
+	t.Helper()
+	path = filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	src := genfile.Header + ". DO NOT EDIT.\npackage app\n\nvar _ = valgen." + fn + "\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectOptions_SkippedDirs(t *testing.T) {
+	root := t.TempDir()
+	writeGenerated(t, root, "models_validation.gen.go", "ValidateEmail")
+	writeGenerated(t, root, "node_modules/pkg/x_validation.gen.go", "IsURL")
+	writeGenerated(t, root, "vendor/pkg/x_validation.gen.go", "IsMAC")
+
+	g := NewGenerator(vtypes.GenerationConfig{ProjectRoot: root})
+	got, err := g.projectOptions("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != vfcontext.EmailPO {
+		t.Errorf("got options %b, want only the email helper %b", got, vfcontext.EmailPO)
+	}
+}
+
+func TestProjectOptions_UnreadableDir(t *testing.T) {
+	root := t.TempDir()
+	writeGenerated(t, root, "models_validation.gen.go", "ValidateEmail")
+
+	locked := filepath.Join(root, "locked")
+	if err := os.Mkdir(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(locked, 0755) })
+	if _, err := os.ReadDir(locked); err == nil {
+		t.Skip("directory permissions are not enforced for this user")
+	}
+
+	g := NewGenerator(vtypes.GenerationConfig{ProjectRoot: root})
+	got, err := g.projectOptions("")
+	if err != nil {
+		t.Fatalf("unreadable directory stopped the scan: %v", err)
+	}
+	if got != vfcontext.EmailPO {
+		t.Errorf("got options %b, want the email helper %b", got, vfcontext.EmailPO)
+	}
+}
@@ -0,0 +1,148 @@
+package modulegen
+
+const urlCode = `
+// IsURL reports whether s is an absolute URL with a scheme and a host, such
+// as https://example.com/path
+func IsURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// IsURI reports whether s is an absolute URI, one with a scheme, such as
+// mailto:user@example.com or urn:isbn:0451450523
+func IsURI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != ""
+}
+`
+
+const hostnameCode = `
+// IsHostname reports whether s is a hostname as RFC 1123 defines it: dot
+// separated labels of letters, digits and hyphens, each 1 to 63 characters
+// long and neither starting nor ending with a hyphen. A single trailing dot
+// is allowed, and the name is at most 253 characters without it.
+func IsHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+`
+
+const ipCode = `
+// IsIP reports whether s is an IPv4 or IPv6 address without a zone
+func IsIP(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Zone() == ""
+}
+
+// IsIPv4 reports whether s is an IPv4 address in dotted decimal form
+func IsIPv4(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is4()
+}
+
+// IsIPv6 reports whether s is an IPv6 address without a zone. IPv4-mapped
+// addresses such as ::ffff:192.0.2.1 are IPv6 addresses.
+func IsIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is6() && addr.Zone() == ""
+}
+
+// IsCIDR reports whether s is an IP prefix in CIDR notation, such as
+// 10.0.0.0/8. Host bits may be set, as in 10.1.2.3/8 for an interface.
+func IsCIDR(s string) bool {
+	_, err := netip.ParsePrefix(s)
+	return err == nil
+}
+`
+
+const macCode = `
+// IsMAC reports whether s is a MAC-48, EUI-48, EUI-64 or 20-octet
+// InfiniBand address in any form net.ParseMAC accepts
+func IsMAC(s string) bool {
+	_, err := net.ParseMAC(s)
+	return err == nil
+}
+`
+
+const portCode = `
+// IsPort reports whether s is a port number from 1 to 65535 written in
+// decimal, without a sign or leading zeros
+func IsPort(s string) bool {
+	if s == "" || len(s) > 5 || s[0] == '0' {
+		return false
+	}
+
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n <= 65535
+}
+`
+
+const uuidCode = `
+// IsUUID reports whether s is a UUID in its textual form of 8-4-4-4-12
+// hexadecimal digits, such as 123e4567-e89b-12d3-a456-426614174000. Any
+// version and either case is accepted.
+func IsUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+`
+
+const ulidCode = `
+// IsULID reports whether s is a ULID: 26 characters of Crockford's base32 in
+// either case. The first character is at most 7, as larger values overflow
+// the 128 bits a ULID holds.
+func IsULID(s string) bool {
+	if len(s) != 26 || s[0] > '7' {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if !('0' <= c && c <= '9' || 'A' <= c && c <= 'Z') || c == 'I' || c == 'L' || c == 'O' || c == 'U' {
+			return false
+		}
+	}
+	return true
+}
+`
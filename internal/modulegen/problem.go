@@ -0,0 +1,80 @@
+package modulegen
+
+import (
+	"path/filepath"
+
+	"github.com/richardbowden/valforge/internal/vfcontext"
+)
+
+const problemCode = `
+// ProblemType is the "type" member of problem details for validation errors.
+// Set it to a URI documenting your validation errors.
+var ProblemType = "about:blank"
+
+// ProblemTitle is the "title" member of problem details for validation errors
+var ProblemTitle = "Validation failed"
+
+// ProblemDetails is an RFC 7807 problem details document
+type ProblemDetails struct {
+	Type          string         ` + "`json:\"type\"`" + `
+	Title         string         ` + "`json:\"title\"`" + `
+	Status        int            ` + "`json:\"status\"`" + `
+	Detail        string         ` + "`json:\"detail,omitempty\"`" + `
+	Instance      string         ` + "`json:\"instance,omitempty\"`" + `
+	InvalidParams []InvalidParam ` + "`json:\"invalid-params,omitempty\"`" + `
+}
+
+// InvalidParam is one entry of the "invalid-params" extension member
+type InvalidParam struct {
+	Name   string ` + "`json:\"name\"`" + `
+	Reason string ` + "`json:\"reason\"`" + `
+	Code   string ` + "`json:\"code,omitempty\"`" + `
+}
+
+// ProblemDetails renders the error as a 400 Bad Request problem, with an
+// invalid-params entry per field error
+func (e *ValidationError) ProblemDetails() *ProblemDetails {
+	p := &ProblemDetails{
+		Type:          ProblemType,
+		Title:         ProblemTitle,
+		Status:        http.StatusBadRequest,
+		Detail:        e.Error(),
+		InvalidParams: make([]InvalidParam, 0, len(e.Errors)),
+	}
+
+	for _, fe := range e.Errors {
+		p.InvalidParams = append(p.InvalidParams, InvalidParam{
+			Name:   fe.Path,
+			Reason: fe.Message,
+			Code:   fe.Code,
+		})
+	}
+	return p
+}
+
+// ServeHTTP writes the error as application/problem+json, so a
+// *ValidationError can be used directly as an http.Handler
+func (e *ValidationError) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := e.ProblemDetails()
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.RequestURI()
+	}
+	p.Write(w)
+}
+
+// Write sends p with the application/problem+json content type
+func (p *ProblemDetails) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
+`
+
+func (g *Generator) ensureProblemDetails(ctx *vfcontext.Context) error {
+	problemFile := filepath.Join(g.packagePath, "problem.gen.go")
+
+	cb := g.newFile("encoding/json", "net/http")
+	cb.Writeln(problemCode)
+
+	return g.addFile(ctx, problemFile, cb)
+}
@@ -0,0 +1,151 @@
+package modulegen
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/richardbowden/valforge/internal/rules"
+	"github.com/richardbowden/valforge/internal/vfcontext"
+)
+
+const translateCode = `
+// Catalog maps message codes to templates. {field} expands to the field
+// name and every other placeholder to the matching FieldError param.
+type Catalog map[string]string
+
+// Translator renders the message for a field error, e.g. in another language
+type Translator interface {
+	Translate(fe FieldError) string
+}
+
+// CatalogTranslator renders messages from a Catalog, keeping the original
+// message for codes the catalog does not contain
+type CatalogTranslator struct {
+	Catalog Catalog
+}
+
+func (t CatalogTranslator) Translate(fe FieldError) string {
+	tmpl, ok := t.Catalog[fe.Code]
+	if !ok {
+		return fe.Message
+	}
+
+	oldnew := []string{"{field}", fe.Field}
+	for name, value := range fe.Params {
+		oldnew = append(oldnew, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(oldnew...).Replace(tmpl)
+}
+
+// RegisterCatalog merges c into the catalog for lang, adding the language
+// if it is not bundled
+func RegisterCatalog(lang string, c Catalog) {
+	lang = normalizeLang(lang)
+
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+
+	// Catalogs handed out by TranslatorFor are never modified, so build a new one
+	merged := make(Catalog, len(catalogs[lang])+len(c))
+	for code, tmpl := range catalogs[lang] {
+		merged[code] = tmpl
+	}
+	for code, tmpl := range c {
+		merged[code] = tmpl
+	}
+	catalogs[lang] = merged
+}
+
+// LoadCatalog registers a catalog for lang read from a JSON object mapping
+// message codes to templates
+func LoadCatalog(lang string, r io.Reader) error {
+	var c Catalog
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return fmt.Errorf("loading %s catalog: %w", lang, err)
+	}
+	RegisterCatalog(lang, c)
+	return nil
+}
+
+// TranslatorFor returns a translator for a language tag such as "de" or
+// "de-DE", falling back to the base language and then to English
+func TranslatorFor(lang string) Translator {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+
+	for lang = normalizeLang(lang); lang != ""; {
+		if c, ok := catalogs[lang]; ok {
+			return CatalogTranslator{Catalog: c}
+		}
+
+		i := strings.LastIndex(lang, "-")
+		if i < 0 {
+			break
+		}
+		lang = lang[:i]
+	}
+	return CatalogTranslator{Catalog: catalogs["en"]}
+}
+
+// Localize returns a copy of e with its messages rendered by t. Custom
+// messages are kept as written.
+func (e *ValidationError) Localize(t Translator) *ValidationError {
+	out := &ValidationError{
+		Struct: e.Struct,
+		Errors: make([]FieldError, len(e.Errors)),
+	}
+	for i, fe := range e.Errors {
+		if !fe.Custom {
+			fe.Message = t.Translate(fe)
+		}
+		out.Errors[i] = fe
+	}
+	return out
+}
+
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+}
+`
+
+func (g *Generator) ensureTranslations(ctx *vfcontext.Context) error {
+	translateFile := filepath.Join(g.packagePath, "translate.gen.go")
+
+	cb := g.newFile("encoding/json", "fmt", "io", "strings", "sync")
+	cb.Writeln(translateCode)
+
+	// translate.gen.go is shared by every package, so per-package messages
+	// stay in the generated validation code and the length unit is the
+	// project's, see rules.SameUnit
+	catalogs := rules.Catalogs(g.config.ProjectMessages, g.config.ProjectLength)
+
+	cb.Writeln("var (")
+	cb.Indent()
+	cb.Writeln("catalogsMu sync.RWMutex")
+	cb.Writeln("catalogs   = map[string]Catalog{")
+	cb.Indent()
+	for _, lang := range sortedKeys(catalogs) {
+		cb.Printf("%s: {", cb.Quote(lang))
+		cb.Indent()
+		for _, code := range sortedKeys(catalogs[lang]) {
+			cb.Printf("%s: %s,", cb.Quote(code), cb.Quote(catalogs[lang][code]))
+		}
+		cb.Dedent()
+		cb.Writeln("},")
+	}
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Dedent()
+	cb.Writeln(")")
+
+	return g.addFile(ctx, translateFile, cb)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
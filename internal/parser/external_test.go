@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/richardbowden/valforge/internal/sidecar"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+func TestExternalRules_MergeAndConflicts(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"user.go": `package models
+
+type User struct {
+	Name  string ` + "`json:\"name\" validate:\"maxlen=10\"`" + `
+	Email string ` + "`json:\"email\"`" + `
+}
+`,
+		"order.go": `package models
+
+type Order struct {
+	Total int
+}
+`,
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	external := sidecar.Rules{
+		"models.User":    {"Email": "required,email", "name": "maxlen=20", "Phone": "required"},
+		"Order":          {"Total": "gt=0"},
+		"Invoice":        {"Total": "gt=0"},
+		"models.Invoice": {"Total": "gt=0"},
+		"billing.Tax":    {"Rate": "gt=0"},
+	}
+
+	t.Run("file", func(t *testing.T) {
+		p := New(vtypes.GenerationConfig{})
+		p.SetExternalRules(external)
+		structs, _, err := p.ParseFile(filepath.Join(dir, "user.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(structs) != 1 || len(structs[0].Fields) != 2 {
+			t.Fatalf("got %+v, want User with two fields", structs)
+		}
+		if got, want := structs[0].Fields[1].Rules, map[string]string{"required": "", "email": ""}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Email rules %v, want %v", got, want)
+		}
+
+		// Invoice may be declared in a file not parsed
+		assertErrors(t, p.Errors(),
+			"rule 'maxlen' is '10' in the tag but '20' in the rules file",
+			"rules file declares rules for unknown field 'Phone' of 'User'",
+		)
+	})
+
+	// An unqualified Invoice may be a type of another package
+	t.Run("package", func(t *testing.T) {
+		p := New(vtypes.GenerationConfig{})
+		p.SetExternalRules(external)
+		structs, _, err := p.ParsePackage(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Order has no tags but is validated for its external rules
+		var order bool
+		for _, s := range structs {
+			order = order || s.Name == "Order"
+		}
+		if !order {
+			t.Errorf("Order not validated")
+		}
+
+		assertErrors(t, p.Errors(),
+			"rule 'maxlen' is '10' in the tag but '20' in the rules file",
+			"rules file declares rules for unknown field 'Phone' of 'User'",
+			"rules file declares rules for unknown type 'models.Invoice'",
+		)
+	})
+}
+
+// assertErrors checks errs holds exactly the given messages, in any order
+func assertErrors(t *testing.T, errs vtypes.CompilerErrors, messages ...string) {
+	t.Helper()
+	if len(errs) != len(messages) {
+		t.Errorf("got %d errors, want %d: %v", len(errs), len(messages), errs)
+	}
+	for _, message := range messages {
+		found := false
+		for _, e := range errs {
+			found = found || strings.Contains(e.Message, message)
+		}
+		if !found {
+			t.Errorf("missing error %q in %v", message, errs)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzParseStructTags(f *testing.F) {
+	f.Add(`json:"name" validate:"required,maxlen=10"`)
+	f.Add(`validate:"contains=\"quoted\",startswith=a b"`)
+	f.Add(`validate:"required`)
+	f.Add(`validate:"\x00" vmsg:"minlen=too short;required=Enter one"`)
+	f.Add(`:"" validate:`)
+
+	f.Fuzz(func(t *testing.T, tag string) {
+		for key := range parseStructTags(tag) {
+			if key == "" || strings.ContainsAny(key, ":\"") {
+				t.Fatalf("parseStructTags(%q) returned key %q", tag, key)
+			}
+		}
+	})
+}
+
+func FuzzParseRuleEntries(f *testing.F) {
+	f.Add("required,email")
+	f.Add("gt=0 || true")
+	f.Add("required@create,required@update,omitempty@update")
+	f.Add("contains=@,endswith=a@b.c,excludes=@@")
+	f.Add("maxlen=10@create,maxlen=20@update")
+	f.Add(",,=,@,=@x")
+
+	f.Fuzz(func(t *testing.T, tag string) {
+		rules := make(map[string]string)
+		groups := make(map[string][]string)
+
+		for _, e := range parseRuleEntries(tag) {
+			if strings.Contains(e.name, ",") || strings.Contains(e.name, "=") {
+				t.Fatalf("entry name %q of %q holds a separator", e.name, tag)
+			}
+			if strings.Contains(e.value, ",") {
+				t.Fatalf("entry value %q of %q holds a comma", e.value, tag)
+			}
+			if e.group != "" && !isGroupWord(e.group) {
+				t.Fatalf("entry group %q of %q is not a word", e.group, tag)
+			}
+
+			addRule(rules, groups, e)
+		}
+
+		for name, ruleGroups := range dropGroups(rules, groups) {
+			if _, ok := rules[name]; !ok {
+				t.Fatalf("groups %v kept for missing rule %q", ruleGroups, name)
+			}
+			if len(ruleGroups) == 0 {
+				t.Fatalf("rule %q has an empty group list", name)
+			}
+		}
+	})
+}
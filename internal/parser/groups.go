@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// groupSeparator limits a rule to a validation group, e.g. required@create
+const groupSeparator = "@"
+
+// literalRules take free text values, in which @ is usually part of the
+// text, as in contains=@ or endswith=@example.com
+var literalRules = map[string]bool{
+	"contains":   true,
+	"excludes":   true,
+	"startswith": true,
+	"endswith":   true,
+}
+
+// ruleEntry is one comma separated entry of a validate tag
+type ruleEntry struct {
+	name  string
+	value string
+	group string // Validation group the rule is limited to, "" for all
+	err   string // Why the entry is ambiguous, "" when it is not
+}
+
+// parseRuleEntries splits a validate tag into its entries. A group is given
+// after the rule name, as in required@create or contains@create=text, or
+// after a numeric or field name value, as in maxlen=10@update. A value of
+// any other kind ending in @word is reported as ambiguous rather than
+// guessed at; @@ in a value stands for a literal @.
+func parseRuleEntries(validateTag string) []ruleEntry {
+	var entries []ruleEntry
+
+	for _, part := range strings.Split(validateTag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var e ruleEntry
+		var hasValue bool
+		e.name, e.value, hasValue = strings.Cut(part, "=")
+		e.name = strings.TrimSpace(e.name)
+
+		if at := strings.LastIndex(e.name, groupSeparator); at > 0 && isGroupWord(e.name[at+1:]) {
+			e.name, e.group = e.name[:at], e.name[at+1:]
+		} else if hasValue {
+			e.value, e.group, e.err = splitValueGroup(e.name, e.value)
+		}
+
+		e.value = strings.ReplaceAll(e.value, groupSeparator+groupSeparator, groupSeparator)
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+// splitValueGroup splits a trailing @group off the value of rule name. The
+// group is only taken when the value before it is a number or a field name;
+// otherwise errMsg explains the ambiguity.
+func splitValueGroup(name, value string) (rest, group, errMsg string) {
+	at := strings.LastIndex(value, groupSeparator)
+	if at < 0 || !isGroupWord(value[at+1:]) || escapedAt(value, at) {
+		return value, "", ""
+	}
+
+	rest, group = value[:at], value[at+1:]
+	if !literalRules[name] && isPlainValue(rest) {
+		return rest, group, ""
+	}
+
+	errMsg = fmt.Sprintf("value '%s' of rule '%s' ends in '@%s', which may be a validation group: write %s@%s=%s to limit the rule to group '%s', or %s=%s@@%s for a literal @",
+		value, name, group, name, group, rest, group, name, rest, group)
+	return value, "", errMsg
+}
+
+// escapedAt reports whether the @ at index at is the second of an @@ pair,
+// counting the run of @ it ends
+func escapedAt(value string, at int) bool {
+	run := 0
+	for i := at; i >= 0 && value[i] == '@'; i-- {
+		run++
+	}
+	return run%2 == 0
+}
+
+// isPlainValue reports whether a rule value is a number or a field name,
+// which cannot end in @ themselves
+func isPlainValue(value string) bool {
+	if token.IsIdentifier(value) {
+		return true
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+func isGroupWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// addRule records e in rules, and its group in groups. A rule given without
+// a group applies to every group. It reports false when the rule is already
+// set to a different value, as one rule has one value across its groups.
+func addRule(rules map[string]string, groups map[string][]string, e ruleEntry) bool {
+	value, exists := rules[e.name]
+
+	switch {
+	case !exists:
+		rules[e.name] = e.value
+		if e.group != "" {
+			groups[e.name] = []string{e.group}
+		}
+	case e.group == "":
+		rules[e.name] = e.value
+		delete(groups, e.name)
+	case value != e.value:
+		return false
+	case groups[e.name] != nil && !slices.Contains(groups[e.name], e.group):
+		groups[e.name] = append(groups[e.name], e.group)
+	}
+
+	return true
+}
+
+// dropGroups removes groups of entries no longer in rules, such as modifiers
+func dropGroups(rules map[string]string, groups map[string][]string) map[string][]string {
+	for name := range groups {
+		if _, ok := rules[name]; !ok {
+			delete(groups, name)
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	return groups
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRuleEntries_Groups(t *testing.T) {
+	tests := []struct {
+		tag       string
+		name      string
+		value     string
+		group     string
+		ambiguous bool
+	}{
+		{tag: "required@create", name: "required", group: "create"},
+		{tag: "maxlen=10@update", name: "maxlen", value: "10", group: "update"},
+		{tag: "gt=-1.5@update", name: "gt", value: "-1.5", group: "update"},
+		{tag: "eqfield=Password@create", name: "eqfield", value: "Password", group: "create"},
+		{tag: "contains@create=foo@bar", name: "contains", value: "foo@bar", group: "create"},
+		{tag: "contains=@", name: "contains", value: "@"},
+		{tag: "endswith=@example.com", name: "endswith", value: "@example.com"},
+		{tag: "contains=foo@@bar", name: "contains", value: "foo@bar"},
+		{tag: "contains=foo@bar", name: "contains", value: "foo@bar", ambiguous: true},
+		{tag: "endswith=ops@example", name: "endswith", value: "ops@example", ambiguous: true},
+		{tag: "eqfield=a.b@create", name: "eqfield", value: "a.b@create", ambiguous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			entries := parseRuleEntries(tt.tag)
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+
+			e := entries[0]
+			if e.name != tt.name || e.value != tt.value || e.group != tt.group {
+				t.Errorf("got name %q value %q group %q, want %q %q %q", e.name, e.value, e.group, tt.name, tt.value, tt.group)
+			}
+			if (e.err != "") != tt.ambiguous {
+				t.Errorf("ambiguous = %v (%q), want %v", e.err != "", e.err, tt.ambiguous)
+			}
+			if tt.ambiguous && !strings.Contains(e.err, tt.name+"@") {
+				t.Errorf("error %q does not suggest the name@group form", e.err)
+			}
+		})
+	}
+}
@@ -7,19 +7,74 @@ import (
 	"go/parser"
 	"go/token"
 	"go/types"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/richardbowden/valforge/internal/sidecar"
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
 
+const (
+	// DefaultTagName is the struct tag read when no tag name is configured
+	DefaultTagName = "validate"
+
+	// DefaultNameFrom is the tag naming fields in errors when none is configured
+	DefaultNameFrom = "json"
+
+	// NameFromGo reports errors under the Go field name
+	NameFromGo = "go"
+
+	// MessageTagName is the struct tag holding per-field message overrides,
+	// e.g. vmsg:"minlen=Password too short;required=Enter a password"
+	MessageTagName = "vmsg"
+
+	// omitEmptyModifier skips a field's rules when it holds its zero value
+	omitEmptyModifier = "omitempty"
+
+	// sensitiveModifier keeps a field's value out of its validation errors
+	sensitiveModifier = "sensitive"
+
+	// messageDirective prefixes field comments overriding a message, e.g.
+	// //valforge:msg minlen=Password too short
+	messageDirective = "valforge:msg"
+)
+
 type Parser struct {
-	fset *token.FileSet
-	info *types.Info
+	fset     *token.FileSet
+	info     *types.Info
+	tagName  string
+	nameFrom string
+	external sidecar.Rules
+	receiver string
+
+	dir          string          // Directory of the package being parsed
+	packageName  string          // Name of the package being parsed
+	pointerTypes map[string]bool // Types with pointer methods, see hasPointerMethods
+
+	declared map[string]bool       // Struct types seen while parsing
+	used     map[sidecar.Key]bool  // External rules applied to a field
+	errors   vtypes.CompilerErrors // Conflicts between tags and external rules
 }
 
-func New() *Parser {
+func New(config vtypes.GenerationConfig) *Parser {
+	tagName := config.TagName
+	if tagName == "" {
+		tagName = DefaultTagName
+	}
+
+	nameFrom := config.NameFrom
+	if nameFrom == "" {
+		nameFrom = DefaultNameFrom
+	}
+
 	return &Parser{
-		fset: token.NewFileSet(),
+		tagName:  tagName,
+		nameFrom: nameFrom,
+		receiver: config.Receiver,
+		declared: make(map[string]bool),
+		used:     make(map[sidecar.Key]bool),
+		fset:     token.NewFileSet(),
 		info: &types.Info{
 			Types: make(map[ast.Expr]types.TypeAndValue),
 			Defs:  make(map[*ast.Ident]types.Object),
@@ -33,6 +88,7 @@ func (p *Parser) ParseFile(filePath string) ([]vtypes.ValidationStruct, string,
 	if err != nil {
 		return nil, "", err
 	}
+	p.dir, p.packageName = filepath.Dir(filePath), file.Name.Name
 
 	config := types.Config{
 		Importer: importer.Default(),
@@ -43,22 +99,16 @@ func (p *Parser) ParseFile(filePath string) ([]vtypes.ValidationStruct, string,
 	pkg, err := config.Check("", p.fset, []*ast.File{file}, p.info)
 	if err != nil {
 		// Try without type checking if import resolution fails
-		visitor := &structVisitor{
-			info:        nil, // No type info available
-			structs:     []vtypes.ValidationStruct{},
-			packageName: file.Name.Name,
-		}
+		visitor := p.newVisitor(nil, file.Name.Name) // No type info available
 		ast.Walk(visitor, file)
+		p.checkExternal(file.Name.Name, false)
 		return visitor.structs, file.Name.Name, nil
 	}
 
-	visitor := &structVisitor{
-		info:        p.info,
-		structs:     []vtypes.ValidationStruct{},
-		packageName: pkg.Name(),
-	}
+	visitor := p.newVisitor(p.info, pkg.Name())
 
 	ast.Walk(visitor, file)
+	p.checkExternal(visitor.packageName, false)
 	return visitor.structs, visitor.packageName, nil
 }
 
@@ -82,6 +132,7 @@ func (p *Parser) ParsePackage(packagePath string) ([]vtypes.ValidationStruct, st
 	if len(allFiles) == 0 {
 		return nil, "", fmt.Errorf("no Go files found in package %s", packagePath)
 	}
+	p.dir, p.packageName = packagePath, packageName
 
 	// Type check the entire package
 	config := types.Config{
@@ -97,11 +148,7 @@ func (p *Parser) ParsePackage(packagePath string) ([]vtypes.ValidationStruct, st
 
 	// Visit all files to collect structs
 	for _, file := range allFiles {
-		visitor := &structVisitor{
-			info:        nil,
-			structs:     []vtypes.ValidationStruct{},
-			packageName: packageName,
-		}
+		visitor := p.newVisitor(nil, packageName)
 		if useTypeInfo {
 			visitor.info = p.info
 		}
@@ -109,17 +156,85 @@ func (p *Parser) ParsePackage(packagePath string) ([]vtypes.ValidationStruct, st
 		allStructs = append(allStructs, visitor.structs...)
 	}
 
+	p.checkExternal(packageName, true)
+
 	return allStructs, packageName, nil
 }
 
+// SetExternalRules adds rules declared outside the source. Structs with
+// external rules are validated even when they have no validate tags.
+func (p *Parser) SetExternalRules(rules sidecar.Rules) {
+	p.external = rules
+}
+
+// Errors returns conflicts found between struct tags and external rules,
+// such as external rules for a field that does not exist
+func (p *Parser) Errors() vtypes.CompilerErrors {
+	return p.errors
+}
+
+// checkExternal reports external rules aimed at pkg that matched no field.
+// Unknown types are only reported when the whole package was parsed, as
+// with a single file they may be declared in another one.
+func (p *Parser) checkExternal(pkg string, wholePackage bool) {
+	for _, key := range p.external.ForPackage(pkg, p.declared) {
+		if p.used[key] {
+			continue
+		}
+
+		_, structName, qualified := strings.Cut(key.Struct, ".")
+		if !qualified {
+			structName = key.Struct
+		}
+
+		message := fmt.Sprintf("rules file declares rules for unknown field '%s' of '%s'", key.Field, structName)
+		if !p.declared[structName] {
+			if !wholePackage {
+				continue
+			}
+			message = fmt.Sprintf("rules file declares rules for unknown type '%s'", key.Struct)
+		}
+
+		p.errors.Add(vtypes.CompilerError{
+			Type:    vtypes.ErrorTypeMissing,
+			Message: message,
+			Field:   key.Field,
+			Struct:  structName,
+		})
+	}
+}
+
+func (p *Parser) newVisitor(info *types.Info, packageName string) *structVisitor {
+	return &structVisitor{
+		info:        info,
+		structs:     []vtypes.ValidationStruct{},
+		packageName: packageName,
+		tagName:     p.tagName,
+		nameFrom:    p.nameFrom,
+		external:    p.external,
+		parser:      p,
+	}
+}
+
 type structVisitor struct {
 	info        *types.Info
 	structs     []vtypes.ValidationStruct
 	packageName string
+	tagName     string
+	nameFrom    string
+	external    sidecar.Rules
+	parser      *Parser
+	declDoc     *ast.CommentGroup // Doc of a type declaration with a single spec
 }
 
 func (v *structVisitor) Visit(node ast.Node) ast.Visitor {
 	switch n := node.(type) {
+	case *ast.GenDecl:
+		// Comments above "type T struct" belong to the declaration, not the spec
+		v.declDoc = nil
+		if len(n.Specs) == 1 {
+			v.declDoc = n.Doc
+		}
 	case *ast.TypeSpec:
 		if structType, ok := n.Type.(*ast.StructType); ok {
 			if s := v.parseStruct(n.Name.Name, structType, n); s != nil {
@@ -133,26 +248,47 @@ func (v *structVisitor) Visit(node ast.Node) ast.Visitor {
 func (v *structVisitor) parseStruct(name string, structType *ast.StructType, typeSpec *ast.TypeSpec) *vtypes.ValidationStruct {
 	var fields []vtypes.ValidationField
 	hasValidation := false
+	v.parser.declared[name] = true
 
 	for _, field := range structType.Fields.List {
-		if field.Tag == nil {
-			continue
+		tags := make(map[string]string)
+		if field.Tag != nil {
+			tagValue, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				tagValue = strings.Trim(field.Tag.Value, "`")
+			}
+			tags = parseStructTags(tagValue)
 		}
 
-		tagValue := strings.Trim(field.Tag.Value, "`")
-		tags := parseStructTags(tagValue)
+		validateTag, hasTag := tags[v.tagName]
+
+		for _, fieldName := range field.Names {
+			externalRules, keys := v.external.Field(v.packageName, name, fieldName.Name, protobufName(tags), tagName(tags, "json"))
+			if !hasTag && len(keys) == 0 {
+				continue
+			}
 
-		if validateTag, exists := tags["validate"]; exists {
 			hasValidation = true
-			for _, fieldName := range field.Names {
-				fieldType := v.extractFieldType(field.Type)
-				fields = append(fields, vtypes.ValidationField{
-					Name:     fieldName.Name,
-					Type:     fieldType,
-					JSONName: getJSONName(tags, fieldName.Name),
-					Rules:    parseValidationRules(validateTag),
-				})
+			fieldType := v.extractFieldType(field.Type)
+			rules, groups := v.parseRules(validateTag, name, fieldName.Name)
+			for i, key := range keys {
+				v.parser.used[key] = true
+				v.mergeExternal(rules, groups, parseRuleEntries(externalRules[i]), name, fieldName.Name)
 			}
+			omitIn := groups[omitEmptyModifier]
+			omitEmpty := takeModifier(rules, omitEmptyModifier)
+			sensitive := takeModifier(rules, sensitiveModifier)
+			fields = append(fields, vtypes.ValidationField{
+				Name:      fieldName.Name,
+				Type:      fieldType,
+				JSONName:  getFieldName(tags, v.nameFrom, fieldName.Name),
+				Rules:     rules,
+				Groups:    dropGroups(rules, groups),
+				Messages:  parseMessages(tags[MessageTagName], field.Doc, field.Comment),
+				Sensitive: sensitive,
+				OmitEmpty: omitEmpty,
+				OmitIn:    omitIn,
+			})
 		}
 	}
 
@@ -160,13 +296,114 @@ func (v *structVisitor) parseStruct(name string, structType *ast.StructType, typ
 		return nil
 	}
 
+	markSecureFields(fields)
+
 	return &vtypes.ValidationStruct{
-		Name:        name,
-		PackageName: v.packageName,
-		Fields:      fields,
+		Name:            name,
+		PackageName:     v.packageName,
+		Fields:          fields,
+		PointerReceiver: v.pointerReceiver(typeSpec, structType),
+	}
+}
+
+// parseRules reads the rules of a validate tag and the groups they are
+// limited to, reporting rules given different values
+func (v *structVisitor) parseRules(validateTag, structName, fieldName string) (map[string]string, map[string][]string) {
+	rules := make(map[string]string)
+	groups := make(map[string][]string)
+
+	for _, e := range parseRuleEntries(validateTag) {
+		if !v.unambiguous(e, structName, fieldName) {
+			continue
+		}
+		if !addRule(rules, groups, e) {
+			v.parser.errors.Add(vtypes.CompilerError{
+				Type:    vtypes.ErrorTypeDuplicate,
+				Message: fmt.Sprintf("rule '%s' is given both '%s' and '%s'", e.name, rules[e.name], e.value),
+				Field:   fieldName,
+				Struct:  structName,
+				Rule:    e.name,
+			})
+		}
+	}
+
+	return rules, groups
+}
+
+// unambiguous reports an entry whose group could not be told apart from its
+// value, and false for it
+func (v *structVisitor) unambiguous(e ruleEntry, structName, fieldName string) bool {
+	if e.err == "" {
+		return true
+	}
+
+	v.parser.errors.Add(vtypes.CompilerError{
+		Type:    vtypes.ErrorTypeInvalid,
+		Message: e.err,
+		Field:   fieldName,
+		Struct:  structName,
+		Rule:    e.name,
+	})
+	return false
+}
+
+// mergeExternal adds external rules to those from the tag, reporting rules
+// set to different values in both
+func (v *structVisitor) mergeExternal(rules map[string]string, groups map[string][]string, external []ruleEntry, structName, fieldName string) {
+	for _, e := range external {
+		if !v.unambiguous(e, structName, fieldName) {
+			continue
+		}
+		if tagValue, ok := rules[e.name]; ok && tagValue != e.value {
+			v.parser.errors.Add(vtypes.CompilerError{
+				Type:    vtypes.ErrorTypeDuplicate,
+				Message: fmt.Sprintf("rule '%s' is '%s' in the tag but '%s' in the rules file", e.name, tagValue, e.value),
+				Field:   fieldName,
+				Struct:  structName,
+				Rule:    e.name,
+			})
+			continue
+		}
+		addRule(rules, groups, e)
 	}
 }
 
+// isProtoMessage reports whether a struct was generated by protoc-gen-go.
+// Such messages embed a protoimpl.MessageState that must not be copied, so
+// they need a pointer receiver.
+func isProtoMessage(structType *ast.StructType) bool {
+	for _, field := range structType.Fields.List {
+		sel, ok := field.Type.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "MessageState" {
+			continue
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "protoimpl" {
+			return true
+		}
+	}
+	return false
+}
+
+// protobufName returns the proto field name from a protoc-gen-go tag such
+// as protobuf:"bytes,1,opt,name=email,proto3"
+func protobufName(tags map[string]string) string {
+	for _, part := range strings.Split(tags["protobuf"], ",") {
+		if name, ok := strings.CutPrefix(part, "name="); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// tagName returns the name part of a tag such as json:"email,omitempty"
+func tagName(tags map[string]string, key string) string {
+	name, _, _ := strings.Cut(tags[key], ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
 func (v *structVisitor) extractFieldType(expr ast.Expr) vtypes.FieldType {
 	ft := vtypes.FieldType{}
 
@@ -193,14 +430,14 @@ func (v *structVisitor) extractFieldType(expr ast.Expr) vtypes.FieldType {
 		}
 	case *ast.ArrayType:
 		ft.IsSlice = true
-		if v.info != nil {
-			if innerType, ok := v.info.Types[t.Elt]; ok {
-				ft.Underlying = innerType.Type
-				ft.Kind = classifyType(innerType.Type)
-			}
-		} else {
-			// Fallback: analyze AST structure
-			ft.Kind = inferTypeFromAST(t.Elt)
+		v.extractElemType(&ft, t.Elt)
+	case *ast.MapType:
+		ft.IsMap = true
+		v.extractElemType(&ft, t.Value)
+		if ft.Kind != vtypes.TypeStruct {
+			// Only dive reads into maps, so values of other kinds stay unknown
+			// and rules for them reject the field
+			ft.Kind, ft.Underlying = vtypes.TypeUnknown, nil
 		}
 	default:
 		if ft.Kind == vtypes.TypeUnknown {
@@ -212,6 +449,29 @@ func (v *structVisitor) extractFieldType(expr ast.Expr) vtypes.FieldType {
 	return ft
 }
 
+// extractElemType describes the elements of a slice or map in ft. Pointer
+// elements are only described for structs, which dive validates.
+func (v *structVisitor) extractElemType(ft *vtypes.FieldType, elem ast.Expr) {
+	if star, ok := elem.(*ast.StarExpr); ok {
+		inner := vtypes.FieldType{}
+		v.extractElemType(&inner, star.X)
+		if inner.Kind == vtypes.TypeStruct {
+			ft.Underlying, ft.Kind, ft.IsElemPtr = inner.Underlying, inner.Kind, true
+		}
+		return
+	}
+
+	if v.info != nil {
+		if innerType, ok := v.info.Types[elem]; ok {
+			ft.Underlying = innerType.Type
+			ft.Kind = classifyType(innerType.Type)
+		}
+	} else {
+		// Fallback: analyze AST structure
+		ft.Kind = inferTypeFromAST(elem)
+	}
+}
+
 // inferTypeFromAST attempts to infer the type from AST structure when type checking fails
 func inferTypeFromAST(expr ast.Expr) vtypes.TypeKind {
 	switch t := expr.(type) {
@@ -246,8 +506,14 @@ func inferTypeFromAST(expr ast.Expr) vtypes.TypeKind {
 		case "bool":
 			return vtypes.TypeBool
 		default:
+			// Without type information, take declared types to be structs
+			if types.Universe.Lookup(t.Name) == nil {
+				return vtypes.TypeStruct
+			}
 			return vtypes.TypeUnknown
 		}
+	case *ast.SelectorExpr:
+		return vtypes.TypeStruct
 	case *ast.StarExpr:
 		return inferTypeFromAST(t.X)
 	case *ast.ArrayType:
@@ -262,6 +528,10 @@ func classifyType(t types.Type) vtypes.TypeKind {
 		return vtypes.TypeUnknown
 	}
 
+	if _, ok := t.Underlying().(*types.Struct); ok {
+		return vtypes.TypeStruct
+	}
+
 	basic, ok := t.Underlying().(*types.Basic)
 	if !ok {
 		return vtypes.TypeUnknown
@@ -301,32 +571,95 @@ func classifyType(t types.Type) vtypes.TypeKind {
 	}
 }
 
+// parseStructTags splits a struct tag into its key:"value" pairs following
+// the reflect.StructTag conventions, so quoted values may contain spaces
 func parseStructTags(tag string) map[string]string {
 	tags := make(map[string]string)
-	parts := strings.Fields(tag)
-
-	for _, part := range parts {
-		colonIndex := strings.Index(part, ":")
-		if colonIndex == -1 {
-			continue
-		}
 
-		key := part[:colonIndex]
-		value := part[colonIndex+1:]
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
 
-		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
-			value = value[1 : len(value)-1]
+		// Keys are runs of non-space, non-control characters other than
+		// colon and quote, as reflect.StructTag reads them
+		colonIndex := 0
+		for colonIndex < len(tag) && tag[colonIndex] > ' ' && tag[colonIndex] != ':' && tag[colonIndex] != '"' && tag[colonIndex] != 0x7f {
+			colonIndex++
+		}
+		if colonIndex == 0 || colonIndex+1 >= len(tag) || tag[colonIndex] != ':' || tag[colonIndex+1] != '"' {
+			break
+		}
+		key := tag[:colonIndex]
+		tag = tag[colonIndex+1:]
+
+		// Find the closing quote, skipping escaped characters
+		i := 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
 		}
 
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
 		tags[key] = value
+		tag = tag[i+1:]
 	}
 
 	return tags
 }
 
-func getJSONName(tags map[string]string, fieldName string) string {
-	if jsonTag, exists := tags["json"]; exists {
-		parts := strings.Split(jsonTag, ",")
+// parseMessages collects a field's message overrides from the vmsg tag and
+// from //valforge:msg comment directives, keyed by message code. Entries in
+// the tag are separated by semicolons as messages often contain commas.
+func parseMessages(tag string, comments ...*ast.CommentGroup) map[string]string {
+	var entries []string
+	if tag != "" {
+		entries = strings.Split(tag, ";")
+	}
+
+	for _, group := range comments {
+		if group == nil {
+			continue
+		}
+		for _, c := range group.List {
+			text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+			if rest, ok := strings.CutPrefix(text, messageDirective+" "); ok {
+				entries = append(entries, rest)
+			}
+		}
+	}
+
+	messages := make(map[string]string)
+	for _, entry := range entries {
+		code, msg, ok := strings.Cut(entry, "=")
+		code = strings.TrimSpace(code)
+		if !ok || code == "" {
+			continue
+		}
+		messages[code] = strings.TrimSpace(msg)
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+	return messages
+}
+
+// getFieldName returns the name errors are reported under, taken from the
+// nameFrom tag, or the Go field name when nameFrom is "go"
+func getFieldName(tags map[string]string, nameFrom, fieldName string) string {
+	if nameFrom == NameFromGo {
+		return fieldName
+	}
+
+	if nameTag, exists := tags[nameFrom]; exists {
+		parts := strings.Split(nameTag, ",")
 		if len(parts) > 0 && parts[0] != "" && parts[0] != "-" {
 			return parts[0]
 		}
@@ -334,27 +667,32 @@ func getJSONName(tags map[string]string, fieldName string) string {
 	return toSnakeCase(fieldName)
 }
 
-func parseValidationRules(validateTag string) map[string]string {
-	rules := make(map[string]string)
-	parts := strings.Split(validateTag, ",")
+// takeModifier reports whether the rules contain the modifier and removes
+// it, as modifiers change how rules are generated rather than being rules
+func takeModifier(rules map[string]string, modifier string) bool {
+	if _, ok := rules[modifier]; !ok {
+		return false
+	}
+	delete(rules, modifier)
+	return true
+}
 
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
+// markSecureFields marks both sides of every eqfieldsecure comparison as
+// sensitive, since the rule exists for secrets
+func markSecureFields(fields []vtypes.ValidationField) {
+	secure := make(map[string]bool)
+	for _, f := range fields {
+		if target, ok := f.Rules["eqfieldsecure"]; ok {
+			secure[f.Name] = true
+			secure[target] = true
 		}
+	}
 
-		if strings.Contains(part, "=") {
-			kv := strings.SplitN(part, "=", 2)
-			if len(kv) == 2 {
-				rules[kv[0]] = kv[1]
-			}
-		} else {
-			rules[part] = ""
+	for i := range fields {
+		if secure[fields[i].Name] {
+			fields[i].Sensitive = true
 		}
 	}
-
-	return rules
 }
 
 func toSnakeCase(str string) string {
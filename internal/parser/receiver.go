@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/richardbowden/valforge/internal/genfile"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+const (
+	// ReceiverValue generates Validate with a value receiver
+	ReceiverValue = "value"
+
+	// ReceiverPointer generates Validate with a pointer receiver
+	ReceiverPointer = "pointer"
+
+	// ReceiverAuto follows the receivers of the type's other methods
+	ReceiverAuto = "auto"
+
+	// receiverDirective sets the receiver kind for one struct, e.g.
+	// //valforge:receiver pointer
+	receiverDirective = "valforge:receiver"
+)
+
+// ValidReceiver reports whether mode is a receiver kind, "" being the default
+func ValidReceiver(mode string) bool {
+	switch mode {
+	case "", ReceiverValue, ReceiverPointer, ReceiverAuto:
+		return true
+	}
+	return false
+}
+
+// pointerReceiver decides whether the Validate method of a struct takes a
+// pointer, from its //valforge:receiver directive or the configured mode
+func (v *structVisitor) pointerReceiver(typeSpec *ast.TypeSpec, structType *ast.StructType) bool {
+	if isProtoMessage(structType) {
+		return true
+	}
+
+	mode := v.parser.receiver
+	if directive := receiverFrom(typeSpec.Doc, v.declDoc); directive != "" {
+		if !ValidReceiver(directive) {
+			v.parser.errors.Add(vtypes.CompilerError{
+				Type:    vtypes.ErrorTypeInvalid,
+				Message: fmt.Sprintf("unknown receiver '%s', expected value, pointer or auto", directive),
+				Struct:  typeSpec.Name.Name,
+			})
+		} else {
+			mode = directive
+		}
+	}
+
+	switch mode {
+	case ReceiverPointer:
+		return true
+	case ReceiverAuto:
+		return v.parser.hasPointerMethods(typeSpec.Name)
+	}
+	return false
+}
+
+// receiverFrom returns the receiver kind given by a //valforge:receiver
+// comment, or "" when there is none
+func receiverFrom(groups ...*ast.CommentGroup) string {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, c := range group.List {
+			text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+			if rest, ok := strings.CutPrefix(text, receiverDirective+" "); ok {
+				return strings.TrimSpace(rest)
+			}
+		}
+	}
+	return ""
+}
+
+// hasPointerMethods reports whether the pointer method set of the named type
+// has methods its value method set lacks, not counting those valforge
+// generated. The whole package in the directory is loaded, so methods in
+// files besides the one given to -file count too.
+func (p *Parser) hasPointerMethods(name *ast.Ident) bool {
+	if p.pointerTypes == nil {
+		found, err := pointerTypes(p.dir, p.packageName)
+		if err != nil {
+			p.errors.Add(vtypes.CompilerError{
+				Type:    vtypes.ErrorTypeInvalid,
+				Message: fmt.Sprintf("cannot load the methods of %s for the auto receiver: %v", name.Name, err),
+				Struct:  name.Name,
+			})
+		}
+		p.pointerTypes = found
+	}
+	return p.pointerTypes[name.Name]
+}
+
+// pointerTypes returns the types of package packageName in dir with methods
+// only a pointer has. The files are those go build would compile, without
+// test files and valforge's output, and the package is type checked so
+// methods promoted from embedded fields count too.
+func pointerTypes(dir, packageName string) (map[string]bool, error) {
+	result := make(map[string]bool)
+
+	pkg, err := build.ImportDir(dir, 0)
+	var noGo *build.NoGoError
+	var multiple *build.MultiplePackageError
+	switch {
+	case errors.As(err, &noGo):
+		return result, nil
+	case err != nil && !errors.As(err, &multiple):
+		return result, err
+	}
+
+	// With several packages in dir, files of the others are only listed as invalid
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range slices.Concat(pkg.GoFiles, pkg.CgoFiles, pkg.InvalidGoFiles) {
+		path := filepath.Join(dir, name)
+		if genfile.IsGenerated(path) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil || file.Name.Name != packageName {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	config := types.Config{
+		Importer: importer.Default(),
+		Error: func(err error) {
+			// Unresolved imports only hide the methods they would promote
+		},
+	}
+	checked, _ := config.Check(packageName, fset, files, nil)
+	if checked == nil {
+		return result, nil
+	}
+
+	scope := checked.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || types.IsInterface(obj.Type()) {
+			continue
+		}
+		value := types.NewMethodSet(obj.Type())
+		pointer := types.NewMethodSet(types.NewPointer(obj.Type()))
+		if pointer.Len() > value.Len() {
+			result[name] = true
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+func TestReceiverAuto_SiblingFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"models.go": `package models
+
+type Order struct {
+	ID string ` + "`validate:\"required\"`" + `
+}
+
+type Item struct {
+	SKU string ` + "`validate:\"required\"`" + `
+}
+
+type Cart struct {
+	Items []string ` + "`validate:\"required\"`" + `
+}
+
+func (c Cart) Total() int { return len(c.Items) }
+
+// Refund gets the pointer methods of Order through embedding
+type Refund struct {
+	Order
+	Reason string ` + "`validate:\"required\"`" + `
+}
+
+// Return embeds a pointer, so its value has every method
+type Return struct {
+	*Order
+	Reason string ` + "`validate:\"required\"`" + `
+}
+`,
+		"order.go": `package models
+
+func (o *Order) Cancel() {}
+`,
+		"cart_ignored.go": `//go:build ignore
+
+package models
+
+func (c *Cart) Clear() {}
+`,
+		"tool.go": `package main
+
+func (c *Cart) Dump() {}
+`,
+		"models_validation.gen.go": `// Code generated by valforge. DO NOT EDIT.
+package models
+
+func (i *Item) Validate() error { return nil }
+`,
+		"cart_test.go": `package models
+
+func (c *Cart) reset() {}
+`,
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := New(vtypes.GenerationConfig{Receiver: ReceiverAuto})
+	structs, _, err := p.ParseFile(filepath.Join(dir, "models.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]bool{"Order": true, "Item": false, "Cart": false, "Refund": true, "Return": false}
+	for _, s := range structs {
+		if s.PointerReceiver != want[s.Name] {
+			t.Errorf("%s: PointerReceiver = %v, want %v", s.Name, s.PointerReceiver, want[s.Name])
+		}
+		delete(want, s.Name)
+	}
+	for name := range want {
+		t.Errorf("struct %s not parsed", name)
+	}
+}
+
+func TestReceiver_ModesAndDirective(t *testing.T) {
+	src := `package models
+
+type Plain struct {
+	Name string ` + "`validate:\"required\"`" + `
+}
+
+//valforge:receiver pointer
+type Large struct {
+	Name string ` + "`validate:\"required\"`" + `
+}
+
+//valforge:receiver value
+type Small struct {
+	Name string ` + "`validate:\"required\"`" + `
+}
+
+//valforge:receiver sideways
+type Odd struct {
+	Name string ` + "`validate:\"required\"`" + `
+}
+`
+
+	tests := []struct {
+		mode string
+		want map[string]bool
+	}{
+		{mode: "", want: map[string]bool{"Plain": false, "Large": true, "Small": false, "Odd": false}},
+		{mode: ReceiverValue, want: map[string]bool{"Plain": false, "Large": true, "Small": false, "Odd": false}},
+		{mode: ReceiverPointer, want: map[string]bool{"Plain": true, "Large": true, "Small": false, "Odd": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run("mode "+tt.mode, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "models.go")
+			if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			p := New(vtypes.GenerationConfig{Receiver: tt.mode})
+			structs, _, err := p.ParseFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, s := range structs {
+				if s.PointerReceiver != tt.want[s.Name] {
+					t.Errorf("%s: PointerReceiver = %v, want %v", s.Name, s.PointerReceiver, tt.want[s.Name])
+				}
+			}
+			if errs := p.Errors(); len(errs) != 1 || errs[0].Struct != "Odd" {
+				t.Errorf("expected the unknown receiver of Odd reported, got %v", errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// parseSource parses src as a file of its own with the given config
+func parseSource(t *testing.T, config vtypes.GenerationConfig, src string) []vtypes.ValidationStruct {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "models.go")
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	structs, _, err := New(config).ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return structs
+}
+
+func TestParseFile_TagNameAndNameFrom(t *testing.T) {
+	src := `package models
+
+type Login struct {
+	Email     string ` + "`json:\"email\" yaml:\"mail\" form:\"email_address\" binding:\"required,email\" validate:\"maxlen=5\"`" + `
+	FirstName string ` + "`json:\"-\" binding:\"required\"`" + `
+}
+`
+
+	tests := []struct {
+		name   string
+		config vtypes.GenerationConfig
+		rules  map[string]string // Rules of Email
+		names  []string          // Error names of Email and FirstName
+	}{
+		{
+			name:  "defaults",
+			rules: map[string]string{"maxlen": "5"},
+			names: []string{"email"},
+		},
+		{
+			name:   "binding tag named by form",
+			config: vtypes.GenerationConfig{TagName: "binding", NameFrom: "form"},
+			rules:  map[string]string{"required": "", "email": ""},
+			names:  []string{"email_address", "first_name"},
+		},
+		{
+			name:   "named by yaml",
+			config: vtypes.GenerationConfig{TagName: "binding", NameFrom: "yaml"},
+			rules:  map[string]string{"required": "", "email": ""},
+			names:  []string{"mail", "first_name"},
+		},
+		{
+			name:   "named by go field",
+			config: vtypes.GenerationConfig{TagName: "binding", NameFrom: NameFromGo},
+			rules:  map[string]string{"required": "", "email": ""},
+			names:  []string{"Email", "FirstName"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			structs := parseSource(t, tt.config, src)
+			if len(structs) != 1 || len(structs[0].Fields) != len(tt.names) {
+				t.Fatalf("got %+v, want Login with %d fields", structs, len(tt.names))
+			}
+
+			fields := structs[0].Fields
+			if !reflect.DeepEqual(fields[0].Rules, tt.rules) {
+				t.Errorf("Email rules %v, want %v", fields[0].Rules, tt.rules)
+			}
+			for i, name := range tt.names {
+				if fields[i].JSONName != name {
+					t.Errorf("%s named %q, want %q", fields[i].Name, fields[i].JSONName, name)
+				}
+			}
+		})
+	}
+}
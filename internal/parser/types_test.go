@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+func TestParseFile_NestedTypes(t *testing.T) {
+	src := `package models
+
+type Line struct {
+	SKU string
+}
+
+type Order struct {
+	Line   Line            ` + "`validate:\"dive\"`" + `
+	Ptr    *Line           ` + "`validate:\"dive\"`" + `
+	Lines  []Line          ` + "`validate:\"dive\"`" + `
+	Ptrs   []*Line         ` + "`validate:\"dive\"`" + `
+	ByName map[string]Line ` + "`validate:\"dive\"`" + `
+	Names  []*string       ` + "`validate:\"required\"`" + `
+	Counts map[string]int  ` + "`validate:\"required\"`" + `
+}
+`
+
+	want := map[string]string{
+		"Line":   "struct",
+		"Ptr":    "*struct",
+		"Lines":  "[]struct",
+		"Ptrs":   "[]*struct",
+		"ByName": "map[...]struct",
+		// Only struct elements are described, so other rules reject these
+		"Names":  "[]unknown",
+		"Counts": "map[...]unknown",
+	}
+
+	structs := parseSource(t, vtypes.GenerationConfig{}, src)
+	if len(structs) != 1 {
+		t.Fatalf("got %d structs, want 1", len(structs))
+	}
+	for _, field := range structs[0].Fields {
+		if got := field.Type.String(); got != want[field.Name] {
+			t.Errorf("%s: got type %s, want %s", field.Name, got, want[field.Name])
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardbowden/valforge/internal/diff"
+	"github.com/richardbowden/valforge/internal/genfile"
+	"github.com/richardbowden/valforge/internal/vfcontext"
+)
+
+// ErrStale is returned by CheckStage when generated files differ from what is on disk
+var ErrStale = errors.New("generated files are out of date")
+
+// CheckStage replaces WriteStage in check mode. It compares the generated
+// output and support files against the existing files, prints a unified
+// diff for every stale file and never touches the filesystem. Support files
+// that are no longer needed are stale while they exist.
+type CheckStage struct{}
+
+func (s *CheckStage) Name() string { return "Check" }
+
+func (s *CheckStage) Execute(ctx *vfcontext.Context) error {
+	var stale []string
+	for _, f := range outputFiles(ctx) {
+		existing, err := os.ReadFile(f.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		have := genfile.Normalize(string(existing))
+		want := genfile.Normalize(f.Content)
+		if have == want {
+			continue
+		}
+
+		name := displayPath(ctx, f.Path)
+		oldName := "a/" + name
+		if os.IsNotExist(err) {
+			oldName = "/dev/null"
+		}
+		fmt.Print(diff.Unified(oldName, "b/"+name, have, want))
+		stale = append(stale, name)
+	}
+
+	for _, path := range ctx.StaleFiles {
+		existing, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		name := displayPath(ctx, path)
+		fmt.Print(diff.Unified("a/"+name, "/dev/null", genfile.Normalize(string(existing)), ""))
+		stale = append(stale, name)
+	}
+
+	if len(stale) > 0 {
+		return fmt.Errorf("%w: %s", ErrStale, strings.Join(stale, ", "))
+	}
+
+	return nil
+}
+
+// displayPath returns path relative to the project root, as diff headers
+// expect, or unchanged when it lies outside the project
+func displayPath(ctx *vfcontext.Context, path string) string {
+	root, err := filepath.Abs(ctx.Config.ProjectRoot)
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -15,8 +15,17 @@ func (s *ValforgePackageStage) Name() string { return "Error Package" }
 func (s *ValforgePackageStage) Execute(ctx *vfcontext.Context) error {
 	// Find project root if not set
 	if ctx.Config.ProjectRoot == "" {
-		if ctx.Config.InputFile != "" {
-			root, moduleName, err := project.FindProjectRoot(filepath.Dir(ctx.Config.InputFile))
+		// Standalone validators live in our project, which may not be the
+		// one declaring the validated types
+		anchor := ""
+		if ctx.Config.StandaloneDir != "" {
+			anchor = ctx.Config.StandaloneDir
+		} else if ctx.Config.InputFile != "" {
+			anchor = filepath.Dir(ctx.Config.InputFile)
+		}
+
+		if anchor != "" {
+			root, moduleName, err := project.FindProjectRoot(anchor)
 			if err != nil {
 				ctx.Config.ProjectRoot = "."
 			} else {
@@ -35,6 +44,8 @@ func (s *ValforgePackageStage) Execute(ctx *vfcontext.Context) error {
 		ctx.Config.ValforgePackage = "valgen"
 	}
 
+	ctx.PackageOptions |= ctx.Registry.PackageOptions(ctx.Structs)
+
 	// Generate error package
 	errorGen := modulegen.NewGenerator(ctx.Config)
 	return errorGen.EnsurePackages(ctx)
@@ -6,8 +6,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/richardbowden/valforge/internal/fsutil"
 	"github.com/richardbowden/valforge/internal/generator"
+	"github.com/richardbowden/valforge/internal/genfile"
+	"github.com/richardbowden/valforge/internal/modulegen"
 	"github.com/richardbowden/valforge/internal/parser"
+	"github.com/richardbowden/valforge/internal/project"
+	"github.com/richardbowden/valforge/internal/sidecar"
 	"github.com/richardbowden/valforge/internal/typechecker"
 	"github.com/richardbowden/valforge/internal/vfcontext"
 	"github.com/richardbowden/valforge/internal/vtypes"
@@ -18,7 +23,19 @@ type ParseStage struct{}
 func (s *ParseStage) Name() string { return "Parse" }
 
 func (s *ParseStage) Execute(ctx *vfcontext.Context) error {
-	p := parser.New()
+	if !parser.ValidReceiver(ctx.Config.Receiver) {
+		return fmt.Errorf("unknown receiver '%s', expected value, pointer or auto", ctx.Config.Receiver)
+	}
+
+	p := parser.New(ctx.Config)
+
+	if ctx.Config.RulesFile != "" {
+		external, err := sidecar.Load(ctx.Config.RulesFile)
+		if err != nil {
+			return err
+		}
+		p.SetExternalRules(external)
+	}
 
 	var structs []vtypes.ValidationStruct
 	var packageName string
@@ -39,6 +56,9 @@ func (s *ParseStage) Execute(ctx *vfcontext.Context) error {
 		return err
 	}
 
+	// Conflicts with external rules are reported alongside type check errors
+	ctx.Errors = append(ctx.Errors, p.Errors()...)
+
 	if len(structs) == 0 {
 		return fmt.Errorf("no structs with validation tags found")
 	}
@@ -46,20 +66,110 @@ func (s *ParseStage) Execute(ctx *vfcontext.Context) error {
 	ctx.Structs = structs
 	ctx.Config.PackageName = packageName
 
+	if ctx.Config.StandaloneDir != "" {
+		if err := resolveStandalone(&ctx.Config); err != nil {
+			return err
+		}
+	}
+
 	if ctx.Config.OutputFile == "" {
-		if ctx.Config.InputFile != "" {
-			dir := filepath.Dir(ctx.Config.InputFile)
-			base := filepath.Base(ctx.Config.InputFile)
-			name := strings.TrimSuffix(base, filepath.Ext(base))
-			ctx.Config.OutputFile = filepath.Join(dir, name+"_validation.gen.go")
-		} else {
-			ctx.Config.OutputFile = filepath.Join(ctx.Config.PackagePath, "validation.gen.go")
+		ctx.Config.OutputFile = outputFileName(ctx.Config)
+	}
+
+	return nil
+}
+
+// outputFileName derives the output path from the input. OutputPattern may
+// use {name} for the input file name without extension (the package name in
+// package mode) and {package} for the package name.
+func outputFileName(config vtypes.GenerationConfig) string {
+	dir, name := config.PackagePath, config.PackageName
+	if config.InputFile != "" {
+		dir = filepath.Dir(config.InputFile)
+		base := filepath.Base(config.InputFile)
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	if config.StandaloneDir != "" {
+		dir = config.StandaloneDir
+	}
+
+	pattern := config.OutputPattern
+	if pattern == "" {
+		if config.InputFile == "" && config.StandaloneDir == "" {
+			return filepath.Join(dir, "validation.gen.go")
 		}
+		pattern = "{name}_validation.gen.go"
+	}
+
+	r := strings.NewReplacer("{name}", name, "{package}", config.PackageName)
+	return filepath.Join(dir, r.Replace(pattern))
+}
+
+// resolveStandalone fills in the package name of the standalone output
+// directory and the import path of the validated package, from go.mod when
+// not given
+func resolveStandalone(config *vtypes.GenerationConfig) error {
+	if config.OutputPackage == "" {
+		dir, err := filepath.Abs(config.StandaloneDir)
+		if err != nil {
+			return err
+		}
+		config.OutputPackage = packageNameFor(filepath.Base(dir))
+	}
+
+	if config.OutputPackage == config.PackageName {
+		return fmt.Errorf("standalone package '%s' must not share its name with the validated package", config.OutputPackage)
+	}
+
+	if config.TargetImportPath != "" {
+		return nil
+	}
+
+	src := config.PackagePath
+	if config.InputFile != "" {
+		src = filepath.Dir(config.InputFile)
+	}
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+
+	root, moduleName, err := project.FindProjectRoot(src)
+	if err != nil || moduleName == "" {
+		return fmt.Errorf("cannot determine the import path of %s, set it with -import-path", src)
+	}
+
+	rel, err := filepath.Rel(root, src)
+	if err != nil {
+		return err
+	}
+	config.TargetImportPath = moduleName
+	if rel != "." {
+		config.TargetImportPath += "/" + filepath.ToSlash(rel)
 	}
 
 	return nil
 }
 
+// packageNameFor turns a directory name into a valid package name
+func packageNameFor(dir string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return -1
+	}, dir)
+
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "v" + name
+	}
+	return name
+}
+
 type TypeCheckStage struct{}
 
 func (s *TypeCheckStage) Name() string { return "Type Check" }
@@ -70,6 +180,11 @@ func (s *TypeCheckStage) Execute(ctx *vfcontext.Context) error {
 	for _, st := range ctx.Structs {
 		errors := tc.CheckStruct(st)
 		ctx.Errors = append(ctx.Errors, errors...)
+		ctx.Errors = append(ctx.Errors, tc.CheckGroups(st, ctx.Config.Groups)...)
+
+		if ctx.Config.StandaloneDir != "" {
+			ctx.Errors = append(ctx.Errors, tc.CheckStandalone(st)...)
+		}
 	}
 
 	if ctx.Errors.HasErrors() {
@@ -99,13 +214,61 @@ type WriteStage struct{}
 
 func (s *WriteStage) Name() string { return "Write" }
 
-func (s *WriteStage) Execute(ctx *vfcontext.Context) error {
-	file, err := os.Create(ctx.Config.OutputFile)
+// Execute writes the support files and the validation output, and removes
+// support files no longer needed. Files whose content is unchanged are left
+// alone so their mtimes, and the build cache, are preserved. Concurrent runs
+// take turns through the support package lock, and stale files are found
+// again once the output is written, so a helper another run needs is kept.
+func (s *WriteStage) Execute(ctx *vfcontext.Context) (err error) {
+	support := modulegen.NewGenerator(ctx.Config)
+	lock, err := support.Lock()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if releaseErr := lock.Release(); err == nil {
+			err = releaseErr
+		}
+	}()
+
+	for _, f := range outputFiles(ctx) {
+		if err := writeIfChanged(f); err != nil {
+			return err
+		}
+	}
+
+	stale, err := support.StaleFiles(ctx)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
 
-	_, err = file.WriteString(ctx.Output)
-	return err
+	return nil
+}
+
+// outputFiles lists every file the pipeline produces, support files first
+func outputFiles(ctx *vfcontext.Context) []vfcontext.File {
+	files := append([]vfcontext.File{}, ctx.SupportFiles...)
+	return append(files, vfcontext.File{Path: ctx.Config.OutputFile, Content: ctx.Output})
+}
+
+func writeIfChanged(f vfcontext.File) error {
+	existing, err := os.ReadFile(f.Path)
+	if err == nil && genfile.Normalize(string(existing)) == genfile.Normalize(f.Content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", f.Path, err)
+	}
+
+	if err := fsutil.WriteFileAtomic(f.Path, []byte(f.Content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.Path, err)
+	}
+
+	return nil
 }
@@ -0,0 +1,56 @@
+package rules
+
+import (
+	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// boolField reports whether t is a plain bool, as pointers and slices
+// cannot be tested directly
+func boolField(t vtypes.FieldType) bool {
+	return t.Kind == vtypes.TypeBool && !t.IsPointer && !t.IsSlice
+}
+
+type IsTrueRule struct{}
+
+func (r IsTrueRule) Name() string              { return "istrue" }
+func (r IsTrueRule) Priority() int             { return 3 }
+func (r IsTrueRule) RequiredImports() []string { return nil }
+func (r IsTrueRule) Aliases() []string         { return []string{} }
+
+func (r IsTrueRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return boolField(fieldType)
+}
+
+func (r IsTrueRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["istrue"]; exists {
+		cb.Printf(`if !v.%s {`, cb.Ident(field.Name))
+		cb.Indent()
+		fail(cb, field, "istrue")
+		cb.Dedent()
+		cb.Writeln("}")
+	}
+	return nil
+}
+
+type IsFalseRule struct{}
+
+func (r IsFalseRule) Name() string              { return "isfalse" }
+func (r IsFalseRule) Priority() int             { return 3 }
+func (r IsFalseRule) RequiredImports() []string { return nil }
+func (r IsFalseRule) Aliases() []string         { return []string{} }
+
+func (r IsFalseRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return boolField(fieldType)
+}
+
+func (r IsFalseRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["isfalse"]; exists {
+		cb.Printf(`if v.%s {`, cb.Ident(field.Name))
+		cb.Indent()
+		fail(cb, field, "isfalse")
+		cb.Dedent()
+		cb.Writeln("}")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package rules
+
+import "github.com/richardbowden/valforge/internal/vtypes"
+
+// RegisterBuiltins adds every built-in rule to registry, with length rules
+// counting as config.Length says and dive passing on the context when
+// config.Context is set
+func RegisterBuiltins(registry *Registry, config vtypes.GenerationConfig) {
+	registry.Register(&RequiredRule{})
+	registry.Register(&GreaterThanRule{})
+	registry.Register(&LessThanRule{})
+	registry.Register(&EqualFieldRule{})
+
+	registry.Register(&MinLenRule{Length: config.Length})
+	registry.Register(&MaxLenRule{Length: config.Length})
+	registry.Register(&LenRule{Length: config.Length})
+	registry.Register(&EqualFieldSecureRule{})
+
+	registry.Register(&AlphaRule{})
+	registry.Register(&AlphaNumRule{})
+	registry.Register(&NumericRule{})
+	registry.Register(&ASCIIRule{})
+	registry.Register(&PrintableRule{})
+	registry.Register(&LowercaseRule{})
+	registry.Register(&UppercaseRule{})
+	registry.Register(&ContainsRule{})
+	registry.Register(&ExcludesRule{})
+	registry.Register(&StartsWithRule{})
+	registry.Register(&EndsWithRule{})
+
+	registry.Register(&IsTrueRule{})
+	registry.Register(&IsFalseRule{})
+
+	registry.Register(&EmailRule{})
+	registry.Register(&URLRule{})
+	registry.Register(&URIRule{})
+	registry.Register(&HostnameRule{})
+	registry.Register(&IPRule{})
+	registry.Register(&IPv4Rule{})
+	registry.Register(&IPv6Rule{})
+	registry.Register(&CIDRRule{})
+	registry.Register(&MACRule{})
+	registry.Register(&PortRule{})
+	registry.Register(&UUIDRule{})
+	registry.Register(&ULIDRule{})
+
+	registry.Register(&DiveRule{Context: config.Context})
+}
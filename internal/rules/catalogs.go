@@ -0,0 +1,132 @@
+package rules
+
+import "maps"
+
+// translations are the bundled message catalogs other than English, keyed
+// by language then message code
+var translations = map[string]map[string]string{
+	"de": {
+		"required": "{field} ist erforderlich",
+		"gt":       "{field} muss größer als {value} sein",
+		"gte":      "{field} muss größer oder gleich {value} sein",
+		"lt":       "{field} muss kleiner als {value} sein",
+		"lte":      "{field} muss kleiner oder gleich {value} sein",
+		"eqfield":  "{field} muss mit {other} übereinstimmen",
+		"minlen":   "{field} muss mindestens {min} Zeichen lang sein",
+		"maxlen":   "{field} darf höchstens {max} Zeichen lang sein",
+		"len":      "{field} muss genau {len} Zeichen lang sein",
+		"email":    "{field} muss eine gültige E-Mail-Adresse sein",
+		"istrue":   "{field} muss wahr sein",
+		"isfalse":  "{field} muss falsch sein",
+
+		"alpha":      "{field} darf nur Buchstaben enthalten",
+		"alphanum":   "{field} darf nur Buchstaben und Ziffern enthalten",
+		"numeric":    "{field} darf nur Ziffern enthalten",
+		"ascii":      "{field} darf nur ASCII-Zeichen enthalten",
+		"printable":  "{field} darf nur druckbare Zeichen enthalten",
+		"lowercase":  "{field} muss in Kleinbuchstaben geschrieben sein",
+		"uppercase":  "{field} muss in Großbuchstaben geschrieben sein",
+		"contains":   "{field} muss {value} enthalten",
+		"excludes":   "{field} darf {value} nicht enthalten",
+		"startswith": "{field} muss mit {value} beginnen",
+		"endswith":   "{field} muss mit {value} enden",
+
+		"url":      "{field} muss eine gültige URL sein",
+		"uri":      "{field} muss eine gültige URI sein",
+		"hostname": "{field} muss ein gültiger Hostname sein",
+		"ip":       "{field} muss eine gültige IP-Adresse sein",
+		"ipv4":     "{field} muss eine gültige IPv4-Adresse sein",
+		"ipv6":     "{field} muss eine gültige IPv6-Adresse sein",
+		"cidr":     "{field} muss ein gültiges CIDR-Präfix sein",
+		"mac":      "{field} muss eine gültige MAC-Adresse sein",
+		"port":     "{field} muss eine gültige Portnummer sein",
+		"uuid":     "{field} muss eine gültige UUID sein",
+		"ulid":     "{field} muss eine gültige ULID sein",
+	},
+	"ja": {
+		"required": "{field}は必須です",
+		"gt":       "{field}は{value}より大きくなければなりません",
+		"gte":      "{field}は{value}以上でなければなりません",
+		"lt":       "{field}は{value}未満でなければなりません",
+		"lte":      "{field}は{value}以下でなければなりません",
+		"eqfield":  "{field}は{other}と一致しなければなりません",
+		"minlen":   "{field}は{min}文字以上でなければなりません",
+		"maxlen":   "{field}は{max}文字以内でなければなりません",
+		"len":      "{field}は{len}文字でなければなりません",
+		"email":    "{field}は有効なメールアドレスでなければなりません",
+		"istrue":   "{field}はtrueでなければなりません",
+		"isfalse":  "{field}はfalseでなければなりません",
+
+		"alpha":      "{field}には英字のみ使用できます",
+		"alphanum":   "{field}には英数字のみ使用できます",
+		"numeric":    "{field}には数字のみ使用できます",
+		"ascii":      "{field}にはASCII文字のみ使用できます",
+		"printable":  "{field}には印字可能な文字のみ使用できます",
+		"lowercase":  "{field}は小文字でなければなりません",
+		"uppercase":  "{field}は大文字でなければなりません",
+		"contains":   "{field}には{value}を含める必要があります",
+		"excludes":   "{field}に{value}を含めることはできません",
+		"startswith": "{field}は{value}で始まらなければなりません",
+		"endswith":   "{field}は{value}で終わらなければなりません",
+
+		"url":      "{field}は有効なURLでなければなりません",
+		"uri":      "{field}は有効なURIでなければなりません",
+		"hostname": "{field}は有効なホスト名でなければなりません",
+		"ip":       "{field}は有効なIPアドレスでなければなりません",
+		"ipv4":     "{field}は有効なIPv4アドレスでなければなりません",
+		"ipv6":     "{field}は有効なIPv6アドレスでなければなりません",
+		"cidr":     "{field}は有効なCIDRプレフィックスでなければなりません",
+		"mac":      "{field}は有効なMACアドレスでなければなりません",
+		"port":     "{field}は有効なポート番号でなければなりません",
+		"uuid":     "{field}は有効なUUIDでなければなりません",
+		"ulid":     "{field}は有効なULIDでなければなりません",
+	},
+}
+
+// byteTranslations replace the length templates of translations in bytes mode
+var byteTranslations = map[string]map[string]string{
+	"de": {
+		"minlen": "{field} muss mindestens {min} Bytes lang sein",
+		"maxlen": "{field} darf höchstens {max} Bytes lang sein",
+		"len":    "{field} muss genau {len} Bytes lang sein",
+	},
+	"ja": {
+		"minlen": "{field}は{min}バイト以上でなければなりません",
+		"maxlen": "{field}は{max}バイト以内でなければなりません",
+		"len":    "{field}は{len}バイトでなければなりません",
+	},
+}
+
+// englishOnly are English templates for codes whose generated message is
+// not rendered from DefaultMessages
+var englishOnly = map[string]string{
+	"email": "{field} must be a valid email address",
+}
+
+// Catalogs returns the bundled message catalogs keyed by language. The
+// English catalog is built from DefaultMessages with overrides applied on
+// top, so it matches the messages in generated code. length is the length
+// mode, whose unit the length templates name.
+func Catalogs(overrides map[string]string, length string) map[string]map[string]string {
+	lengthMessages := map[string]string{}
+	if length == LengthBytes {
+		lengthMessages = byteMessages
+	}
+
+	en := make(map[string]string, len(DefaultMessages)+len(englishOnly)+len(overrides))
+	for _, m := range []map[string]string{englishOnly, DefaultMessages, lengthMessages, overrides} {
+		for code, tmpl := range m {
+			en[code] = tmpl
+		}
+	}
+
+	result := map[string]map[string]string{"en": en}
+	for lang, catalog := range translations {
+		if length == LengthBytes {
+			catalog = maps.Clone(catalog)
+			maps.Copy(catalog, byteTranslations[lang])
+		}
+		result[lang] = catalog
+	}
+	return result
+}
@@ -19,19 +19,17 @@ func (r GreaterThanRule) SupportsType(fieldType vtypes.FieldType) bool {
 func (r GreaterThanRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
 
 	if gtVal, exists := field.Rules["gt"]; exists {
-		cb.Printf(`if v.%s <= %s {`, field.Name, gtVal)
+		cb.Printf(`if v.%s <= %s {`, cb.Ident(field.Name), cb.Int(gtVal))
 		cb.Indent()
-		cb.Printf(`verr.AddFieldError("%s", "%s must be greater than %s", v.%s)`,
-			field.JSONName, field.JSONName, gtVal, field.Name)
+		fail(cb, field, "gt", "value", gtVal)
 		cb.Dedent()
 		cb.Writeln("}")
 	}
 
 	if gtVal, exists := field.Rules["gte"]; exists {
-		cb.Printf(`if v.%s < %s {`, field.Name, gtVal)
+		cb.Printf(`if v.%s < %s {`, cb.Ident(field.Name), cb.Int(gtVal))
 		cb.Indent()
-		cb.Printf(`verr.AddFieldError("%s", "%s must be greater than or equal to %s", v.%s)`,
-			field.JSONName, field.JSONName, gtVal, field.Name)
+		fail(cb, field, "gte", "value", gtVal)
 		cb.Dedent()
 		cb.Writeln("}")
 	}
@@ -52,19 +50,17 @@ func (r LessThanRule) SupportsType(fieldType vtypes.FieldType) bool {
 
 func (r LessThanRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
 	if ltVal, exists := field.Rules["lt"]; exists {
-		cb.Printf(`if v.%s >= %s {`, field.Name, ltVal)
+		cb.Printf(`if v.%s >= %s {`, cb.Ident(field.Name), cb.Int(ltVal))
 		cb.Indent()
-		cb.Printf(`verr.AddFieldError("%s", "%s must be less than %s", v.%s)`,
-			field.JSONName, field.JSONName, ltVal, field.Name)
+		fail(cb, field, "lt", "value", ltVal)
 		cb.Dedent()
 		cb.Writeln("}")
 	}
 
 	if gtVal, exists := field.Rules["lte"]; exists {
-		cb.Printf(`if v.%s > %s {`, field.Name, gtVal)
+		cb.Printf(`if v.%s > %s {`, cb.Ident(field.Name), cb.Int(gtVal))
 		cb.Indent()
-		cb.Printf(`verr.AddFieldError("%s", "%s must be less than or equal to %s", v.%s)`,
-			field.JSONName, field.JSONName, gtVal, field.Name)
+		fail(cb, field, "lte", "value", gtVal)
 		cb.Dedent()
 		cb.Writeln("}")
 	}
@@ -85,10 +81,9 @@ func (r EqualFieldRule) SupportsType(fieldType vtypes.FieldType) bool {
 
 func (r EqualFieldRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
 	if targetField, exists := field.Rules["eqfield"]; exists {
-		cb.Printf(`if v.%s != v.%s {`, field.Name, targetField)
+		cb.Printf(`if v.%s != v.%s {`, cb.Ident(field.Name), cb.Ident(targetField))
 		cb.Indent()
-		cb.Printf(`verr.AddFieldError("%s", "%s must match %s", v.%s)`,
-			field.JSONName, field.JSONName, targetField, field.Name)
+		fail(cb, field, "eqfield", "other", targetField)
 		cb.Dedent()
 		cb.Writeln("}")
 	}
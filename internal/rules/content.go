@@ -0,0 +1,246 @@
+package rules
+
+import (
+	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// generateByteLoop emits a loop over the bytes of the field, failing rule at
+// the first byte c for which bad holds. Used for ASCII classes, where bytes
+// of multi-byte characters fail as they should.
+func generateByteLoop(cb *builder.CodeBuilder, field vtypes.ValidationField, rule, bad string) {
+	cb.Printf("for i := 0; i < len(v.%s); i++ {", cb.Ident(field.Name))
+	cb.Indent()
+	cb.Printf("if c := v.%s[i]; %s {", cb.Ident(field.Name), bad)
+	cb.Indent()
+	fail(cb, field, rule)
+	cb.Writeln("break")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Dedent()
+	cb.Writeln("}")
+}
+
+// generateRuneLoop is generateByteLoop over the runes r of the field
+func generateRuneLoop(cb *builder.CodeBuilder, field vtypes.ValidationField, rule, bad string) {
+	cb.Printf("for _, r := range v.%s {", cb.Ident(field.Name))
+	cb.Indent()
+	cb.Printf("if %s {", bad)
+	cb.Indent()
+	fail(cb, field, rule)
+	cb.Writeln("break")
+	cb.Dedent()
+	cb.Writeln("}")
+	cb.Dedent()
+	cb.Writeln("}")
+}
+
+// generateSubstring emits a check failing rule when cond holds. cond is a
+// format with the field and the quoted rule value, in that order.
+func generateSubstring(cb *builder.CodeBuilder, field vtypes.ValidationField, rule, cond string) {
+	value := field.Rules[rule]
+
+	cb.Printf("if "+cond+" {", cb.Ident(field.Name), cb.Quote(value))
+	cb.Indent()
+	fail(cb, field, rule, "value", value)
+	cb.Dedent()
+	cb.Writeln("}")
+}
+
+type AlphaRule struct{}
+
+func (r AlphaRule) Name() string              { return "alpha" }
+func (r AlphaRule) Priority() int             { return 2 }
+func (r AlphaRule) RequiredImports() []string { return nil }
+func (r AlphaRule) Aliases() []string         { return []string{} }
+
+func (r AlphaRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r AlphaRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["alpha"]; exists {
+		generateByteLoop(cb, field, "alpha", "!('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z')")
+	}
+	return nil
+}
+
+type AlphaNumRule struct{}
+
+func (r AlphaNumRule) Name() string              { return "alphanum" }
+func (r AlphaNumRule) Priority() int             { return 2 }
+func (r AlphaNumRule) RequiredImports() []string { return nil }
+func (r AlphaNumRule) Aliases() []string         { return []string{} }
+
+func (r AlphaNumRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r AlphaNumRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["alphanum"]; exists {
+		generateByteLoop(cb, field, "alphanum", "!('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9')")
+	}
+	return nil
+}
+
+type NumericRule struct{}
+
+func (r NumericRule) Name() string              { return "numeric" }
+func (r NumericRule) Priority() int             { return 2 }
+func (r NumericRule) RequiredImports() []string { return nil }
+func (r NumericRule) Aliases() []string         { return []string{} }
+
+func (r NumericRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r NumericRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["numeric"]; exists {
+		generateByteLoop(cb, field, "numeric", "c < '0' || c > '9'")
+	}
+	return nil
+}
+
+type ASCIIRule struct{}
+
+func (r ASCIIRule) Name() string              { return "ascii" }
+func (r ASCIIRule) Priority() int             { return 2 }
+func (r ASCIIRule) RequiredImports() []string { return nil }
+func (r ASCIIRule) Aliases() []string         { return []string{} }
+
+func (r ASCIIRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r ASCIIRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["ascii"]; exists {
+		generateByteLoop(cb, field, "ascii", "c >= 0x80")
+	}
+	return nil
+}
+
+type PrintableRule struct{}
+
+func (r PrintableRule) Name() string              { return "printable" }
+func (r PrintableRule) Priority() int             { return 2 }
+func (r PrintableRule) RequiredImports() []string { return []string{"unicode"} }
+func (r PrintableRule) Aliases() []string         { return []string{} }
+
+func (r PrintableRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r PrintableRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["printable"]; exists {
+		generateRuneLoop(cb, field, "printable", "!unicode.IsPrint(r)")
+	}
+	return nil
+}
+
+type LowercaseRule struct{}
+
+func (r LowercaseRule) Name() string              { return "lowercase" }
+func (r LowercaseRule) Priority() int             { return 2 }
+func (r LowercaseRule) RequiredImports() []string { return []string{"unicode"} }
+func (r LowercaseRule) Aliases() []string         { return []string{} }
+
+func (r LowercaseRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r LowercaseRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["lowercase"]; exists {
+		generateRuneLoop(cb, field, "lowercase", "unicode.IsUpper(r) || unicode.IsTitle(r)")
+	}
+	return nil
+}
+
+type UppercaseRule struct{}
+
+func (r UppercaseRule) Name() string              { return "uppercase" }
+func (r UppercaseRule) Priority() int             { return 2 }
+func (r UppercaseRule) RequiredImports() []string { return []string{"unicode"} }
+func (r UppercaseRule) Aliases() []string         { return []string{} }
+
+func (r UppercaseRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r UppercaseRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["uppercase"]; exists {
+		generateRuneLoop(cb, field, "uppercase", "unicode.IsLower(r) || unicode.IsTitle(r)")
+	}
+	return nil
+}
+
+type ContainsRule struct{}
+
+func (r ContainsRule) Name() string              { return "contains" }
+func (r ContainsRule) Priority() int             { return 2 }
+func (r ContainsRule) RequiredImports() []string { return []string{"strings"} }
+func (r ContainsRule) Aliases() []string         { return []string{} }
+
+func (r ContainsRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r ContainsRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["contains"]; exists {
+		generateSubstring(cb, field, "contains", "!strings.Contains(v.%s, %s)")
+	}
+	return nil
+}
+
+type ExcludesRule struct{}
+
+func (r ExcludesRule) Name() string              { return "excludes" }
+func (r ExcludesRule) Priority() int             { return 2 }
+func (r ExcludesRule) RequiredImports() []string { return []string{"strings"} }
+func (r ExcludesRule) Aliases() []string         { return []string{} }
+
+func (r ExcludesRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r ExcludesRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["excludes"]; exists {
+		generateSubstring(cb, field, "excludes", "strings.Contains(v.%s, %s)")
+	}
+	return nil
+}
+
+type StartsWithRule struct{}
+
+func (r StartsWithRule) Name() string              { return "startswith" }
+func (r StartsWithRule) Priority() int             { return 2 }
+func (r StartsWithRule) RequiredImports() []string { return []string{"strings"} }
+func (r StartsWithRule) Aliases() []string         { return []string{} }
+
+func (r StartsWithRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r StartsWithRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["startswith"]; exists {
+		generateSubstring(cb, field, "startswith", "!strings.HasPrefix(v.%s, %s)")
+	}
+	return nil
+}
+
+type EndsWithRule struct{}
+
+func (r EndsWithRule) Name() string              { return "endswith" }
+func (r EndsWithRule) Priority() int             { return 2 }
+func (r EndsWithRule) RequiredImports() []string { return []string{"strings"} }
+func (r EndsWithRule) Aliases() []string         { return []string{} }
+
+func (r EndsWithRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r EndsWithRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["endswith"]; exists {
+		generateSubstring(cb, field, "endswith", "!strings.HasSuffix(v.%s, %s)")
+	}
+	return nil
+}
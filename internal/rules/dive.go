@@ -0,0 +1,78 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/vfcontext"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// DiveRule validates nested structs with their own Validate methods: a
+// struct, a pointer to one, or the elements of a slice or map of them. With
+// Context set it passes ctx on and stops once ctx is done.
+type DiveRule struct {
+	Context bool
+}
+
+func (r DiveRule) Name() string              { return "dive" }
+func (r DiveRule) Priority() int             { return 5 }
+func (r DiveRule) RequiredImports() []string { return nil }
+func (r DiveRule) Aliases() []string         { return []string{} }
+
+func (r DiveRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.DivePO
+}
+
+func (r DiveRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return fieldType.Kind == vtypes.TypeStruct
+}
+
+func (r DiveRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	name := cb.Ident(field.Name)
+	path := cb.Quote(field.JSONName)
+
+	switch {
+	case field.Type.IsSlice:
+		cb.Printf("for i := range v.%s {", name)
+		cb.Indent()
+		r.dive(cb, fmt.Sprintf("%s.IndexPath(%s, i)", vtypes.SupportAlias, path), fmt.Sprintf("v.%s[i]", name), field.Type.IsElemPtr)
+		cb.Dedent()
+		cb.Writeln("}")
+	case field.Type.IsMap:
+		cb.Printf("for key, elem := range v.%s {", name)
+		cb.Indent()
+		r.dive(cb, fmt.Sprintf("%s.IndexPath(%s, key)", vtypes.SupportAlias, path), "elem", field.Type.IsElemPtr)
+		cb.Dedent()
+		cb.Writeln("}")
+	default:
+		r.dive(cb, path, "v."+name, field.Type.IsPointer)
+	}
+	return nil
+}
+
+// dive emits the validation of one nested value, expr, which is a pointer
+// when pointer is set and addressable otherwise
+func (r DiveRule) dive(cb *builder.CodeBuilder, path, expr string, pointer bool) {
+	if pointer {
+		cb.Printf("if %s != nil {", expr)
+		cb.Indent()
+	} else {
+		expr = "&" + expr
+	}
+
+	if r.Context {
+		cb.Printf("if err := %s.DiveContext(ctx, verr, %s, %s); err != nil {", vtypes.SupportAlias, path, expr)
+		cb.Indent()
+		cb.Writeln("return err")
+		cb.Dedent()
+		cb.Writeln("}")
+	} else {
+		cb.Printf("%s.Dive(verr, %s, %s)", vtypes.SupportAlias, path, expr)
+	}
+
+	if pointer {
+		cb.Dedent()
+		cb.Writeln("}")
+	}
+}
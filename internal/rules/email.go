@@ -2,6 +2,7 @@ package rules
 
 import (
 	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/vfcontext"
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
 
@@ -12,16 +13,23 @@ func (r EmailRule) Priority() int             { return 2 }
 func (r EmailRule) RequiredImports() []string { return nil }
 func (r EmailRule) Aliases() []string         { return []string{} }
 
+func (r EmailRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.EmailPO
+}
+
 func (r EmailRule) SupportsType(fieldType vtypes.FieldType) bool {
 	return StringTypes.Contains(fieldType.Kind)
 }
 
 func (r EmailRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
 
-	cb.Printf("err := valgen.ValidateEmail(v.%s)", field.Name)
-	cb.Printf("if err != nil {")
+	cb.Printf("if err := %s.ValidateEmail(v.%s); err != nil {", vtypes.SupportAlias, cb.Ident(field.Name))
 	cb.Indent()
-	cb.Printf(`verr.AddFieldError("%s", err.Error(), v.%s)`, field.JSONName, field.Name)
+	if _, ok := MessageTemplate(field, "email"); ok {
+		fail(cb, field, "email")
+	} else {
+		failWith(cb, field, "email", "err.Error()")
+	}
 	cb.Dedent()
 	cb.Writeln("}")
 
@@ -0,0 +1,56 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// sharedCodes maps rules to the message code they share with another rule
+var sharedCodes = map[string]string{
+	"eqfieldsecure": "eqfield",
+}
+
+// MessageCode returns the message code used for errors from rule
+func MessageCode(rule string) string {
+	if code, ok := sharedCodes[rule]; ok {
+		return code
+	}
+	return rule
+}
+
+// fail emits code recording that field failed rule, with the message
+// rendered from the rule's template. params are placeholder name and value
+// pairs, also stored on the error for translation.
+func fail(cb *builder.CodeBuilder, field vtypes.ValidationField, rule string, params ...string) {
+	failWith(cb, field, rule, cb.Quote(Message(field, rule, params...)), params...)
+}
+
+// failWith is fail with messageExpr, a Go expression, as the message
+func failWith(cb *builder.CodeBuilder, field vtypes.ValidationField, rule, messageExpr string, params ...string) {
+	cb.Printf("verr.Add(%s.FieldError{", vtypes.SupportAlias)
+	cb.Indent()
+	cb.Printf("Field: %s,", cb.Quote(field.JSONName))
+	cb.Printf("GoField: %s,", cb.Quote(field.Name))
+	cb.Printf("Rule: %s,", cb.Quote(rule))
+	cb.Printf("Code: %s,", cb.Quote(MessageCode(rule)))
+	cb.Printf("Message: %s,", messageExpr)
+	if customMessage(field, rule) {
+		cb.Writeln("Custom: true,")
+	}
+	if len(params) > 0 {
+		pairs := make([]string, 0, len(params)/2)
+		for i := 0; i+1 < len(params); i += 2 {
+			pairs = append(pairs, cb.Quote(params[i])+": "+cb.Quote(params[i+1]))
+		}
+		cb.Printf("Params: map[string]string{%s},", strings.Join(pairs, ", "))
+	}
+	if field.Sensitive {
+		cb.Writeln("Redacted: true,")
+	} else {
+		cb.Printf("Value: v.%s,", cb.Ident(field.Name))
+	}
+	cb.Dedent()
+	cb.Writeln("})")
+}
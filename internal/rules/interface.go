@@ -2,6 +2,7 @@ package rules
 
 import (
 	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/vfcontext"
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
 
@@ -14,6 +15,12 @@ type Rule interface {
 	Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error
 }
 
+// SupportRule is implemented by rules whose generated code calls helpers
+// in the support package, which are only emitted when some field needs them
+type SupportRule interface {
+	PackageOptions(field vtypes.ValidationField) vfcontext.PackageOptions
+}
+
 type TypeSet []vtypes.TypeKind
 
 func (ts TypeSet) Contains(kind vtypes.TypeKind) bool {
@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// DefaultMessages are the built-in message templates keyed by message code.
+// {field} expands to the reported field name, other placeholders to the
+// rule parameters passed to Message.
+var DefaultMessages = map[string]string{
+	"required": "{field} is required",
+	"gt":       "{field} must be greater than {value}",
+	"gte":      "{field} must be greater than or equal to {value}",
+	"lt":       "{field} must be less than {value}",
+	"lte":      "{field} must be less than or equal to {value}",
+	"eqfield":  "{field} must match {other}",
+	"minlen":   "{field} must be at least {min} characters",
+	"maxlen":   "{field} must be at most {max} characters",
+	"len":      "{field} must be exactly {len} characters",
+	"istrue":   "{field} must be true",
+	"isfalse":  "{field} must be false",
+
+	"alpha":      "{field} must contain only letters",
+	"alphanum":   "{field} must contain only letters and digits",
+	"numeric":    "{field} must contain only digits",
+	"ascii":      "{field} must contain only ASCII characters",
+	"printable":  "{field} must contain only printable characters",
+	"lowercase":  "{field} must be lowercase",
+	"uppercase":  "{field} must be uppercase",
+	"contains":   "{field} must contain {value}",
+	"excludes":   "{field} must not contain {value}",
+	"startswith": "{field} must start with {value}",
+	"endswith":   "{field} must end with {value}",
+
+	"url":      "{field} must be a valid URL",
+	"uri":      "{field} must be a valid URI",
+	"hostname": "{field} must be a valid hostname",
+	"ip":       "{field} must be a valid IP address",
+	"ipv4":     "{field} must be a valid IPv4 address",
+	"ipv6":     "{field} must be a valid IPv6 address",
+	"cidr":     "{field} must be a valid CIDR prefix",
+	"mac":      "{field} must be a valid MAC address",
+	"port":     "{field} must be a valid port number",
+	"uuid":     "{field} must be a valid UUID",
+	"ulid":     "{field} must be a valid ULID",
+}
+
+// MessageTemplate returns the field's template for rule, looked up by rule
+// name then by message code, falling back to the built-in default. ok is
+// false when none exists.
+func MessageTemplate(field vtypes.ValidationField, rule string) (tmpl string, ok bool) {
+	if tmpl, ok := field.Messages[rule]; ok {
+		return tmpl, true
+	}
+	code := MessageCode(rule)
+	if tmpl, ok := field.Messages[code]; ok {
+		return tmpl, true
+	}
+	tmpl, ok = DefaultMessages[code]
+	return tmpl, ok
+}
+
+// customMessage reports whether field overrides the template for rule, as
+// vmsg tags, //valforge:msg directives and configured messages do
+func customMessage(field vtypes.ValidationField, rule string) bool {
+	if _, ok := field.Messages[rule]; ok {
+		return true
+	}
+	_, ok := field.Messages[MessageCode(rule)]
+	return ok
+}
+
+// Message renders the message for rule. params are placeholder name and
+// value pairs, e.g. Message(field, "minlen", "min", "8").
+func Message(field vtypes.ValidationField, rule string, params ...string) string {
+	tmpl, _ := MessageTemplate(field, rule)
+	return expand(tmpl, field, params...)
+}
+
+func expand(tmpl string, field vtypes.ValidationField, params ...string) string {
+	oldnew := append([]string{"{field}", field.JSONName}, params...)
+	for i := 2; i < len(oldnew); i += 2 {
+		oldnew[i] = "{" + oldnew[i] + "}"
+	}
+	return strings.NewReplacer(oldnew...).Replace(tmpl)
+}
@@ -0,0 +1,286 @@
+package rules
+
+import (
+	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/vfcontext"
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+// generateFormat emits a check failing rule when helper, a support package
+// function reporting whether a string is well formed, rejects the field
+func generateFormat(cb *builder.CodeBuilder, field vtypes.ValidationField, rule, helper string) {
+	cb.Printf("if !%s.%s(v.%s) {", vtypes.SupportAlias, cb.Ident(helper), cb.Ident(field.Name))
+	cb.Indent()
+	fail(cb, field, rule)
+	cb.Dedent()
+	cb.Writeln("}")
+}
+
+type URLRule struct{}
+
+func (r URLRule) Name() string              { return "url" }
+func (r URLRule) Priority() int             { return 2 }
+func (r URLRule) RequiredImports() []string { return nil }
+func (r URLRule) Aliases() []string         { return []string{} }
+
+func (r URLRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.URLPO
+}
+
+func (r URLRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r URLRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["url"]; exists {
+		generateFormat(cb, field, "url", "IsURL")
+	}
+	return nil
+}
+
+type URIRule struct{}
+
+func (r URIRule) Name() string              { return "uri" }
+func (r URIRule) Priority() int             { return 2 }
+func (r URIRule) RequiredImports() []string { return nil }
+func (r URIRule) Aliases() []string         { return []string{} }
+
+func (r URIRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.URLPO
+}
+
+func (r URIRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r URIRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["uri"]; exists {
+		generateFormat(cb, field, "uri", "IsURI")
+	}
+	return nil
+}
+
+type HostnameRule struct{}
+
+func (r HostnameRule) Name() string              { return "hostname" }
+func (r HostnameRule) Priority() int             { return 2 }
+func (r HostnameRule) RequiredImports() []string { return nil }
+func (r HostnameRule) Aliases() []string         { return []string{} }
+
+func (r HostnameRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.HostnamePO
+}
+
+func (r HostnameRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r HostnameRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["hostname"]; exists {
+		generateFormat(cb, field, "hostname", "IsHostname")
+	}
+	return nil
+}
+
+type IPRule struct{}
+
+func (r IPRule) Name() string              { return "ip" }
+func (r IPRule) Priority() int             { return 2 }
+func (r IPRule) RequiredImports() []string { return nil }
+func (r IPRule) Aliases() []string         { return []string{} }
+
+func (r IPRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.IPPO
+}
+
+func (r IPRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r IPRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["ip"]; exists {
+		generateFormat(cb, field, "ip", "IsIP")
+	}
+	return nil
+}
+
+type IPv4Rule struct{}
+
+func (r IPv4Rule) Name() string              { return "ipv4" }
+func (r IPv4Rule) Priority() int             { return 2 }
+func (r IPv4Rule) RequiredImports() []string { return nil }
+func (r IPv4Rule) Aliases() []string         { return []string{} }
+
+func (r IPv4Rule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.IPPO
+}
+
+func (r IPv4Rule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r IPv4Rule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["ipv4"]; exists {
+		generateFormat(cb, field, "ipv4", "IsIPv4")
+	}
+	return nil
+}
+
+type IPv6Rule struct{}
+
+func (r IPv6Rule) Name() string              { return "ipv6" }
+func (r IPv6Rule) Priority() int             { return 2 }
+func (r IPv6Rule) RequiredImports() []string { return nil }
+func (r IPv6Rule) Aliases() []string         { return []string{} }
+
+func (r IPv6Rule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.IPPO
+}
+
+func (r IPv6Rule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r IPv6Rule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["ipv6"]; exists {
+		generateFormat(cb, field, "ipv6", "IsIPv6")
+	}
+	return nil
+}
+
+type CIDRRule struct{}
+
+func (r CIDRRule) Name() string              { return "cidr" }
+func (r CIDRRule) Priority() int             { return 2 }
+func (r CIDRRule) RequiredImports() []string { return nil }
+func (r CIDRRule) Aliases() []string         { return []string{} }
+
+func (r CIDRRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.IPPO
+}
+
+func (r CIDRRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r CIDRRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["cidr"]; exists {
+		generateFormat(cb, field, "cidr", "IsCIDR")
+	}
+	return nil
+}
+
+type MACRule struct{}
+
+func (r MACRule) Name() string              { return "mac" }
+func (r MACRule) Priority() int             { return 2 }
+func (r MACRule) RequiredImports() []string { return nil }
+func (r MACRule) Aliases() []string         { return []string{} }
+
+func (r MACRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.MACPO
+}
+
+func (r MACRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r MACRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["mac"]; exists {
+		generateFormat(cb, field, "mac", "IsMAC")
+	}
+	return nil
+}
+
+// portTypes are the integer types able to hold every port number
+var portTypes = TypeSet{
+	vtypes.TypeInt, vtypes.TypeInt32, vtypes.TypeInt64,
+	vtypes.TypeUint, vtypes.TypeUint16, vtypes.TypeUint32, vtypes.TypeUint64,
+}
+
+// PortRule accepts port numbers 1 to 65535, held as integers or as strings
+// of decimal digits. The support package helper is only needed for strings.
+type PortRule struct{}
+
+func (r PortRule) Name() string              { return "port" }
+func (r PortRule) Priority() int             { return 2 }
+func (r PortRule) RequiredImports() []string { return nil }
+func (r PortRule) Aliases() []string         { return []string{} }
+
+func (r PortRule) PackageOptions(field vtypes.ValidationField) vfcontext.PackageOptions {
+	if field.Type.Kind == vtypes.TypeString {
+		return vfcontext.PortPO
+	}
+	return 0
+}
+
+func (r PortRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind) || portTypes.Contains(fieldType.Kind)
+}
+
+func (r PortRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["port"]; !exists {
+		return nil
+	}
+
+	switch field.Type.Kind {
+	case vtypes.TypeString:
+		generateFormat(cb, field, "port", "IsPort")
+		return nil
+	case vtypes.TypeUint16:
+		// 65535 is the largest uint16, so only zero is out of range
+		cb.Printf("if v.%s == 0 {", cb.Ident(field.Name))
+	default:
+		cb.Printf("if v.%[1]s < 1 || v.%[1]s > 65535 {", cb.Ident(field.Name))
+	}
+	cb.Indent()
+	fail(cb, field, "port")
+	cb.Dedent()
+	cb.Writeln("}")
+
+	return nil
+}
+
+type UUIDRule struct{}
+
+func (r UUIDRule) Name() string              { return "uuid" }
+func (r UUIDRule) Priority() int             { return 2 }
+func (r UUIDRule) RequiredImports() []string { return nil }
+func (r UUIDRule) Aliases() []string         { return []string{} }
+
+func (r UUIDRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.UUIDPO
+}
+
+func (r UUIDRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r UUIDRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["uuid"]; exists {
+		generateFormat(cb, field, "uuid", "IsUUID")
+	}
+	return nil
+}
+
+type ULIDRule struct{}
+
+func (r ULIDRule) Name() string              { return "ulid" }
+func (r ULIDRule) Priority() int             { return 2 }
+func (r ULIDRule) RequiredImports() []string { return nil }
+func (r ULIDRule) Aliases() []string         { return []string{} }
+
+func (r ULIDRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return vfcontext.ULIDPO
+}
+
+func (r ULIDRule) SupportsType(fieldType vtypes.FieldType) bool {
+	return StringTypes.Contains(fieldType.Kind)
+}
+
+func (r ULIDRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
+	if _, exists := field.Rules["ulid"]; exists {
+		generateFormat(cb, field, "ulid", "IsULID")
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package rules
 
 import (
+	"sort"
+
 	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/vfcontext"
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
 
@@ -23,6 +26,21 @@ func (r *Registry) Register(rule Rule) {
 	r.rules[rule.Name()] = rule
 }
 
+// Restrict removes every rule not named in names, so tags using them are
+// reported as unknown. Aliases must be listed on their own to stay enabled.
+func (r *Registry) Restrict(names []string) {
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		enabled[name] = true
+	}
+
+	for name := range r.rules {
+		if !enabled[name] {
+			delete(r.rules, name)
+		}
+	}
+}
+
 func (r *Registry) Get(name string) (Rule, bool) {
 	rule, exists := r.rules[name]
 	return rule, exists
@@ -75,6 +93,32 @@ func (r *Registry) GetRequiredImports(fields []vtypes.ValidationField) []string
 	return result
 }
 
+// PackageOptions returns the support package helpers needed by the rules
+// used in structs
+func (r *Registry) PackageOptions(structs []vtypes.ValidationStruct) vfcontext.PackageOptions {
+	var options vfcontext.PackageOptions
+	for _, s := range structs {
+		for _, field := range s.Fields {
+			for ruleName := range field.Rules {
+				if rule, ok := r.rules[ruleName].(SupportRule); ok {
+					options |= rule.PackageOptions(field)
+				}
+			}
+		}
+	}
+	return options
+}
+
+// RuleNames returns the names of every registered rule and alias, sorted
+func (r *Registry) RuleNames() []string {
+	names := make([]string, 0, len(r.rules))
+	for name := range r.rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (r *Registry) HasRule(name string) bool {
 	_, exists := r.rules[name]
 	return exists
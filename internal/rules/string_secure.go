@@ -18,9 +18,9 @@ func (r EqualFieldSecureRule) SupportsType(fieldType vtypes.FieldType) bool {
 
 func (r EqualFieldSecureRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
 	if targetField, exists := field.Rules["eqfieldsecure"]; exists {
-		cb.Printf(`if subtle.ConstantTimeCompare([]byte(v.%s), []byte(v.%s)) == 0 {`, field.Name, targetField)
+		cb.Printf(`if subtle.ConstantTimeCompare([]byte(v.%s), []byte(v.%s)) == 0 {`, cb.Ident(field.Name), cb.Ident(targetField))
 		cb.Indent()
-		cb.Printf(`verr.AddFieldError("%s", "%s must match %s", v.%s)`, field.JSONName, field.JSONName, targetField, field.Name)
+		fail(cb, field, "eqfieldsecure", "other", targetField)
 		cb.Dedent()
 		cb.Writeln("}")
 	}
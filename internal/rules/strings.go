@@ -1,75 +1,171 @@
 package rules
 
 import (
+	"fmt"
+
 	"github.com/richardbowden/valforge/internal/builder"
+	"github.com/richardbowden/valforge/internal/vfcontext"
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
 
-type MinLenRule struct{}
+// Length modes choose what minlen, maxlen and len count
+const (
+	LengthRunes     = "runes"     // Unicode code points, the default
+	LengthBytes     = "bytes"     // UTF-8 bytes, e.g. to fit a column size
+	LengthGraphemes = "graphemes" // User-perceived characters, e.g. a flag emoji counts once
+)
+
+// ValidLength reports whether mode is a length mode, "" being the default
+func ValidLength(mode string) bool {
+	switch mode {
+	case "", LengthRunes, LengthBytes, LengthGraphemes:
+		return true
+	}
+	return false
+}
+
+// SameUnit reports whether length modes a and b name the same unit in
+// messages. Runes and graphemes both count characters, so only bytes differs.
+func SameUnit(a, b string) bool {
+	return (a == LengthBytes) == (b == LengthBytes)
+}
+
+// byteMessages replace the length templates in bytes mode, so messages do
+// not promise characters
+var byteMessages = map[string]string{
+	"minlen": "{field} must be at least {min} bytes",
+	"maxlen": "{field} must be at most {max} bytes",
+	"len":    "{field} must be exactly {len} bytes",
+}
+
+// lengthExpr returns the expression measuring a field in the given mode
+func lengthExpr(cb *builder.CodeBuilder, mode string, field vtypes.ValidationField) string {
+	switch mode {
+	case LengthBytes:
+		return fmt.Sprintf("len(v.%s)", cb.Ident(field.Name))
+	case LengthGraphemes:
+		return fmt.Sprintf("%s.GraphemeCount(v.%s)", vtypes.SupportAlias, cb.Ident(field.Name))
+	}
+	return fmt.Sprintf("utf8.RuneCountInString(v.%s)", cb.Ident(field.Name))
+}
+
+// lengthOptions returns the support package helpers the mode measures with
+func lengthOptions(mode string) vfcontext.PackageOptions {
+	if mode == LengthGraphemes {
+		return vfcontext.GraphemePO
+	}
+	return 0
+}
+
+func lengthImports(mode string) []string {
+	if mode == LengthRunes || mode == "" {
+		return []string{"unicode/utf8"}
+	}
+	return nil
+}
+
+// withLengthMessages adds the bytes mode templates to the field unless its
+// messages override them. Only the rendered message uses the result, so the
+// bytes templates are not taken for custom ones.
+func withLengthMessages(mode string, field vtypes.ValidationField) vtypes.ValidationField {
+	if mode != LengthBytes {
+		return field
+	}
+
+	messages := make(map[string]string, len(field.Messages)+len(byteMessages))
+	for code, tmpl := range byteMessages {
+		messages[code] = tmpl
+	}
+	for code, tmpl := range field.Messages {
+		messages[code] = tmpl
+	}
+	field.Messages = messages
+	return field
+}
+
+// generateLength emits a length check failing when the measured length
+// compared with op against value is true
+func generateLength(cb *builder.CodeBuilder, mode string, field vtypes.ValidationField, rule, op, param, value string) {
+	message := Message(withLengthMessages(mode, field), rule, param, value)
+
+	cb.Printf(`if %s %s %s {`, lengthExpr(cb, mode, field), op, cb.Int(value))
+	cb.Indent()
+	failWith(cb, field, rule, cb.Quote(message), param, value)
+	cb.Dedent()
+	cb.Writeln("}")
+}
+
+// MinLenRule checks a minimum string length, counted as Length says
+type MinLenRule struct {
+	Length string
+}
 
 func (r MinLenRule) Name() string              { return "minlen" }
 func (r MinLenRule) Priority() int             { return 2 }
-func (r MinLenRule) RequiredImports() []string { return nil }
+func (r MinLenRule) RequiredImports() []string { return lengthImports(r.Length) }
 func (r MinLenRule) Aliases() []string         { return []string{} }
 
+func (r MinLenRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return lengthOptions(r.Length)
+}
+
 func (r MinLenRule) SupportsType(fieldType vtypes.FieldType) bool {
 	return StringTypes.Contains(fieldType.Kind)
 }
 
 func (r MinLenRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
 	if minVal, exists := field.Rules["minlen"]; exists {
-		cb.Printf(`if len(v.%s) < %s {`, field.Name, minVal)
-		cb.Indent()
-		cb.Printf(`verr.AddFieldError("%s", "%s must be at least %s characters", v.%s)`,
-			field.JSONName, field.JSONName, minVal, field.Name)
-		cb.Dedent()
-		cb.Writeln("}")
+		generateLength(cb, r.Length, field, "minlen", "<", "min", minVal)
 	}
 	return nil
 }
 
-type MaxLenRule struct{}
+// MaxLenRule checks a maximum string length, counted as Length says
+type MaxLenRule struct {
+	Length string
+}
 
 func (r MaxLenRule) Name() string              { return "maxlen" }
 func (r MaxLenRule) Priority() int             { return 2 }
-func (r MaxLenRule) RequiredImports() []string { return nil }
+func (r MaxLenRule) RequiredImports() []string { return lengthImports(r.Length) }
 func (r MaxLenRule) Aliases() []string         { return []string{} }
 
+func (r MaxLenRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return lengthOptions(r.Length)
+}
+
 func (r MaxLenRule) SupportsType(fieldType vtypes.FieldType) bool {
 	return StringTypes.Contains(fieldType.Kind)
 }
 
 func (r MaxLenRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
 	if maxVal, exists := field.Rules["maxlen"]; exists {
-		cb.Printf(`if len(v.%s) > %s {`, field.Name, maxVal)
-		cb.Indent()
-		cb.Printf(`verr.AddFieldError("%s", "%s must be at most %s characters", v.%s)`,
-			field.JSONName, field.JSONName, maxVal, field.Name)
-		cb.Dedent()
-		cb.Writeln("}")
+		generateLength(cb, r.Length, field, "maxlen", ">", "max", maxVal)
 	}
 	return nil
 }
 
-type LenRule struct{}
+// LenRule checks an exact string length, counted as Length says
+type LenRule struct {
+	Length string
+}
 
 func (r LenRule) Name() string              { return "len" }
 func (r LenRule) Priority() int             { return 2 }
-func (r LenRule) RequiredImports() []string { return nil }
+func (r LenRule) RequiredImports() []string { return lengthImports(r.Length) }
 func (r LenRule) Aliases() []string         { return []string{} }
 
+func (r LenRule) PackageOptions(vtypes.ValidationField) vfcontext.PackageOptions {
+	return lengthOptions(r.Length)
+}
+
 func (r LenRule) SupportsType(fieldType vtypes.FieldType) bool {
 	return StringTypes.Contains(fieldType.Kind)
 }
 
 func (r LenRule) Generate(cb *builder.CodeBuilder, field vtypes.ValidationField, structName string) error {
 	if lenVal, exists := field.Rules["len"]; exists {
-		cb.Printf(`if len(v.%s) != %s {`, field.Name, lenVal)
-		cb.Indent()
-		cb.Printf(`verr.AddFieldError("%s", "%s must be exactly %s characters", v.%s)`,
-			field.JSONName, field.JSONName, lenVal, field.Name)
-		cb.Dedent()
-		cb.Writeln("}")
+		generateLength(cb, r.Length, field, "len", "!=", "len", lenVal)
 	}
 	return nil
 }
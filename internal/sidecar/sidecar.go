@@ -0,0 +1,150 @@
+package sidecar
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the rules files looked for next to go.mod, in order
+var FileNames = []string{"valforge.rules.yaml", "valforge.rules.yml", "valforge.rules.toml"}
+
+// Rules declares validation rules outside the source, keyed by struct then
+// field. Struct keys are a type name, optionally qualified by package name
+// ("User" or "models.User"). Each value uses the validate tag syntax, e.g.
+// "required,email". Fields may be named by Go name, json name or protobuf
+// field name.
+type Rules map[string]map[string]string
+
+// Find returns the first rules file present in dir, or "" when there is none
+func Find(dir string) string {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Load reads a rules file, choosing the format from its extension. Entries
+// are either nested by struct:
+//
+//	models.User:
+//	  Email: required,email
+//
+// or flat with the field in the key:
+//
+//	models.User.Email: required,email
+func Load(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		_, err = toml.Decode(string(data), &raw)
+	default:
+		return nil, fmt.Errorf("%s: unsupported rules format", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	rules := make(Rules)
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			dot := strings.LastIndex(key, ".")
+			if dot <= 0 || dot == len(key)-1 {
+				return nil, fmt.Errorf("%s: '%s' must name a type and field, e.g. models.User.Email", path, key)
+			}
+			rules.add(key[:dot], key[dot+1:], v)
+		case map[string]any:
+			for field, fieldRules := range v {
+				s, ok := fieldRules.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s: rules for '%s.%s' must be a string", path, key, field)
+				}
+				rules.add(key, field, s)
+			}
+		default:
+			return nil, fmt.Errorf("%s: '%s' must map fields to rules or be a rules string", path, key)
+		}
+	}
+
+	return rules, nil
+}
+
+func (r Rules) add(structKey, field, rules string) {
+	if r[structKey] == nil {
+		r[structKey] = make(map[string]string)
+	}
+	r[structKey][field] = rules
+}
+
+// Field returns the rules declared for a field of structName in package
+// pkg under any of the field's names, and the keys they were found under
+// for reporting unused entries
+func (r Rules) Field(pkg, structName string, names ...string) (rules []string, keys []Key) {
+	for _, structKey := range []string{pkg + "." + structName, structName} {
+		fields, exists := r[structKey]
+		if !exists {
+			continue
+		}
+
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+			if fieldRules, exists := fields[name]; exists {
+				rules = append(rules, fieldRules)
+				keys = append(keys, Key{Struct: structKey, Field: name})
+			}
+		}
+	}
+	return rules, keys
+}
+
+// Key identifies one entry of a rules file
+type Key struct {
+	Struct string
+	Field  string
+}
+
+func (k Key) String() string {
+	return k.Struct + "." + k.Field
+}
+
+// ForPackage lists the entries that target package pkg, given the struct
+// names declared in it. Qualified entries for other packages, and
+// unqualified entries for structs the package does not declare, are left
+// out as they may belong to another package.
+func (r Rules) ForPackage(pkg string, structs map[string]bool) []Key {
+	var keys []Key
+	for structKey, fields := range r {
+		qualifier, _, qualified := strings.Cut(structKey, ".")
+		if qualified && qualifier != pkg {
+			continue
+		}
+		if !qualified && !structs[structKey] {
+			continue
+		}
+
+		for field := range fields {
+			keys = append(keys, Key{Struct: structKey, Field: field})
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
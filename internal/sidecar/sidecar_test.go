@@ -0,0 +1,103 @@
+package sidecar
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeRules(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_NestedAndFlat(t *testing.T) {
+	files := map[string]string{
+		"valforge.rules.yaml": `
+models.User.Email: required,email
+models.User:
+  Name: required,maxlen=100
+Order:
+  total: gt=0
+`,
+		"valforge.rules.toml": `
+"models.User.Email" = "required,email"
+
+["models.User"]
+Name = "required,maxlen=100"
+
+[Order]
+total = "gt=0"
+`,
+	}
+
+	want := Rules{
+		"models.User": {"Email": "required,email", "Name": "required,maxlen=100"},
+		"Order":       {"total": "gt=0"},
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			got, err := Load(writeRules(t, name, content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"Email: required\n",
+		"models.User.: required\n",
+		"models.User:\n  Tags: [a, b]\n",
+		"models.User: 3\n",
+	} {
+		if _, err := Load(writeRules(t, "valforge.rules.yaml", content)); err == nil {
+			t.Errorf("expected an error loading %q", content)
+		}
+	}
+}
+
+func TestRules_Field(t *testing.T) {
+	rules := Rules{
+		"models.User": {"Email": "required"},
+		"User":        {"email": "email"},
+		"Order":       {"Total": "gt=0"},
+	}
+
+	got, keys := rules.Field("models", "User", "Email", "email", "")
+	if want := []string{"required", "email"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got rules %v, want %v", got, want)
+	}
+	if want := []Key{{"models.User", "Email"}, {"User", "email"}}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got keys %v, want %v", keys, want)
+	}
+
+	if got, _ := rules.Field("billing", "Order", "Total"); !reflect.DeepEqual(got, []string{"gt=0"}) {
+		t.Errorf("got %v for an unqualified struct", got)
+	}
+}
+
+func TestRules_ForPackage(t *testing.T) {
+	rules := Rules{
+		"models.User":  {"Email": "required"},
+		"billing.User": {"Email": "required"},
+		"Order":        {"Total": "gt=0"},
+		"Invoice":      {"Total": "gt=0"},
+	}
+
+	got := rules.ForPackage("models", map[string]bool{"User": true, "Order": true})
+	want := []Key{{"Order", "Total"}, {"models.User", "Email"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
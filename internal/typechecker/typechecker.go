@@ -2,7 +2,11 @@ package typechecker
 
 import (
 	"fmt"
+	"go/token"
+	"maps"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
@@ -33,11 +37,157 @@ func (tc *TypeChecker) CheckStruct(s vtypes.ValidationStruct) vtypes.CompilerErr
 	for _, field := range s.Fields {
 		fieldErrors := tc.checkField(field, s.Name, fieldMap)
 		errors = append(errors, fieldErrors...)
+
+		if err := tc.checkOmitEmpty(field, s.Name); err != nil {
+			errors.Add(*err)
+		}
 	}
 
 	return errors
 }
 
+// CheckStandalone reports fields of s that a function in another package
+// cannot validate: unexported fields, and dives, as the nested types there
+// get ValidateT functions rather than methods
+func (tc *TypeChecker) CheckStandalone(s vtypes.ValidationStruct) vtypes.CompilerErrors {
+	var errors vtypes.CompilerErrors
+
+	for _, field := range s.Fields {
+		if !token.IsExported(field.Name) {
+			errors.Add(vtypes.CompilerError{
+				Type:    vtypes.ErrorTypeInvalid,
+				Message: fmt.Sprintf("field '%s' is unexported and cannot be validated from another package", field.Name),
+				Field:   field.Name,
+				Struct:  s.Name,
+			})
+		}
+		if _, ok := field.Rules["dive"]; ok {
+			errors.Add(vtypes.CompilerError{
+				Type:    vtypes.ErrorTypeInvalid,
+				Message: "dive is not supported by standalone validators",
+				Field:   field.Name,
+				Struct:  s.Name,
+				Rule:    "dive",
+			})
+		}
+	}
+
+	return errors
+}
+
+// reservedGroups would give wrappers the names of other generated methods
+var reservedGroups = map[string]bool{"context": true, "groups": true}
+
+// CheckGroups reports group names that cannot name a generated method, two
+// groups naming the same method, and groups missing from known unless known
+// is empty
+func (tc *TypeChecker) CheckGroups(s vtypes.ValidationStruct, known []string) vtypes.CompilerErrors {
+	var errors vtypes.CompilerErrors
+
+	// methods maps the generated method suffixes to the group naming them
+	methods := make(map[string]string)
+
+	for _, field := range s.Fields {
+		limited := field.Groups
+		if len(field.OmitIn) > 0 {
+			limited = maps.Clone(limited)
+			if limited == nil {
+				limited = make(map[string][]string)
+			}
+			limited["omitempty"] = field.OmitIn
+		}
+
+		for _, rule := range slices.Sorted(maps.Keys(limited)) {
+			for _, group := range limited[rule] {
+				var message string
+				switch {
+				case !validGroupName(group):
+					message = fmt.Sprintf("group '%s' must be lowercase letters and digits, optionally separated by underscores", group)
+				case reservedGroups[group]:
+					message = fmt.Sprintf("group '%s' is reserved", group)
+				case len(known) > 0 && !slices.Contains(known, group):
+					message = fmt.Sprintf("unknown group '%s', expected one of %s", group, strings.Join(known, ", "))
+				default:
+					method := vtypes.GroupMethodName(group)
+					other, ok := methods[method]
+					if !ok || other == group {
+						methods[method] = group
+						continue
+					}
+					message = fmt.Sprintf("groups '%s' and '%s' both generate Validate%s", other, group, method)
+				}
+
+				errors.Add(vtypes.CompilerError{
+					Type:    vtypes.ErrorTypeInvalid,
+					Message: message,
+					Field:   field.Name,
+					Struct:  s.Name,
+					Rule:    rule,
+				})
+			}
+		}
+	}
+
+	return errors
+}
+
+// validGroupName accepts names such as create or bulk_update
+func validGroupName(name string) bool {
+	for i, word := range strings.Split(name, "_") {
+		if word == "" {
+			return false
+		}
+		for j, r := range word {
+			if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' && (i > 0 || j > 0) {
+				continue
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// checkOmitEmpty reports omitempty on types without a zero check, and with
+// rules it would always skip: required, and istrue on a plain bool
+func (tc *TypeChecker) checkOmitEmpty(field vtypes.ValidationField, structName string) *vtypes.CompilerError {
+	if !field.OmitEmpty {
+		return nil
+	}
+
+	if _, ok := field.Type.NonZero("v." + field.Name); !ok {
+		return &vtypes.CompilerError{
+			Type:    vtypes.ErrorTypeIncompatible,
+			Message: fmt.Sprintf("omitempty is not compatible with type '%s'", field.Type),
+			Field:   field.Name,
+			Struct:  structName,
+			Rule:    "omitempty",
+		}
+	}
+
+	if len(field.OmitIn) > 0 {
+		return nil
+	}
+
+	// Rules failing only on the zero value, which omitempty skips
+	zeroOnly := []string{"required"}
+	if field.Type.Kind == vtypes.TypeBool && !field.Type.IsPointer && !field.Type.IsSlice {
+		zeroOnly = append(zeroOnly, "istrue")
+	}
+	for _, rule := range zeroOnly {
+		if _, exists := field.Rules[rule]; exists {
+			return &vtypes.CompilerError{
+				Type:    vtypes.ErrorTypeInvalid,
+				Message: fmt.Sprintf("omitempty skips the field when empty, so '%s' can never fail", rule),
+				Field:   field.Name,
+				Struct:  structName,
+				Rule:    "omitempty",
+			}
+		}
+	}
+
+	return nil
+}
+
 func (tc *TypeChecker) checkField(field vtypes.ValidationField, structName string, fieldMap map[string]vtypes.ValidationField) vtypes.CompilerErrors {
 	var errors vtypes.CompilerErrors
 
@@ -56,9 +206,13 @@ func (tc *TypeChecker) checkField(field vtypes.ValidationField, structName strin
 
 		// Check if rule is compatible with field type
 		if !rule.SupportsType(field.Type) {
+			message := fmt.Sprintf("rule '%s' is not compatible with type '%s'", ruleName, field.Type)
+			if ruleName == "required" && field.Type.Kind == vtypes.TypeBool && !field.Type.IsPointer {
+				message += ", use istrue, or *bool to require an answer"
+			}
 			errors.Add(vtypes.CompilerError{
 				Type:    vtypes.ErrorTypeIncompatible,
-				Message: fmt.Sprintf("rule '%s' is not compatible with type '%s'", ruleName, field.Type.Kind),
+				Message: message,
 				Field:   field.Name,
 				Struct:  structName,
 				Rule:    ruleName,
@@ -127,6 +281,27 @@ func (tc *TypeChecker) validateRuleParams(ruleName string, field vtypes.Validati
 				Rule:    ruleName,
 			}
 		}
+	case "contains", "excludes", "startswith", "endswith":
+		if ruleValue == "" {
+			return &vtypes.CompilerError{
+				Type:    vtypes.ErrorTypeInvalid,
+				Message: fmt.Sprintf("rule '%s' requires a value", ruleName),
+				Field:   field.Name,
+				Struct:  structName,
+				Rule:    ruleName,
+			}
+		}
+	case "istrue", "isfalse", "alpha", "alphanum", "numeric", "ascii", "printable", "lowercase", "uppercase",
+		"url", "uri", "hostname", "ip", "ipv4", "ipv6", "cidr", "mac", "port", "uuid", "ulid":
+		if ruleValue != "" {
+			return &vtypes.CompilerError{
+				Type:    vtypes.ErrorTypeInvalid,
+				Message: fmt.Sprintf("rule '%s' takes no value", ruleName),
+				Field:   field.Name,
+				Struct:  structName,
+				Rule:    ruleName,
+			}
+		}
 	case "eqfield":
 		if ruleValue == "" {
 			return &vtypes.CompilerError{
@@ -152,7 +327,7 @@ func (tc *TypeChecker) validateRuleParams(ruleName string, field vtypes.Validati
 		if field.Type.Kind != targetField.Type.Kind {
 			return &vtypes.CompilerError{
 				Type:    vtypes.ErrorTypeIncompatible,
-				Message: fmt.Sprintf("eqfield field types must match: '%s' vs '%s'", field.Type.Kind, targetField.Type.Kind),
+				Message: fmt.Sprintf("eqfield field types must match: '%s' vs '%s'", field.Type, targetField.Type),
 				Field:   field.Name,
 				Struct:  structName,
 				Rule:    ruleName,
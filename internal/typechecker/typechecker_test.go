@@ -0,0 +1,112 @@
+package typechecker
+
+import (
+	"testing"
+
+	"github.com/richardbowden/valforge/internal/vtypes"
+)
+
+func TestCheckOmitEmpty(t *testing.T) {
+	boolType := vtypes.FieldType{Kind: vtypes.TypeBool}
+	tests := []struct {
+		name  string
+		field vtypes.ValidationField
+		fails bool
+	}{
+		{"string", vtypes.ValidationField{Type: vtypes.FieldType{Kind: vtypes.TypeString}, Rules: map[string]string{"minlen": "3"}}, false},
+		{"required", vtypes.ValidationField{Type: vtypes.FieldType{Kind: vtypes.TypeString}, Rules: map[string]string{"required": ""}}, true},
+		{"required omitted in group", vtypes.ValidationField{Type: vtypes.FieldType{Kind: vtypes.TypeString}, Rules: map[string]string{"required": ""}, OmitIn: []string{"update"}}, false},
+		{"istrue", vtypes.ValidationField{Type: boolType, Rules: map[string]string{"istrue": ""}}, true},
+		{"isfalse", vtypes.ValidationField{Type: boolType, Rules: map[string]string{"isfalse": ""}}, false},
+		{"istrue omitted in group", vtypes.ValidationField{Type: boolType, Rules: map[string]string{"istrue": ""}, OmitIn: []string{"update"}}, false},
+		{"istrue on pointer", vtypes.ValidationField{Type: vtypes.FieldType{Kind: vtypes.TypeBool, IsPointer: true}, Rules: map[string]string{"istrue": ""}}, false},
+	}
+
+	tc := New(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.field.Name = "Field"
+			tt.field.OmitEmpty = true
+			err := tc.checkOmitEmpty(tt.field, "S")
+			if (err != nil) != tt.fails {
+				t.Errorf("checkOmitEmpty = %v, want error %v", err, tt.fails)
+			}
+		})
+	}
+}
+
+func TestCheckStandalone(t *testing.T) {
+	s := vtypes.ValidationStruct{
+		Name: "User",
+		Fields: []vtypes.ValidationField{
+			{Name: "Email", Rules: map[string]string{"email": ""}},
+			{Name: "secret", Rules: map[string]string{"required": ""}},
+			{Name: "Address", Rules: map[string]string{"dive": ""}},
+		},
+	}
+
+	errs := New(nil).CheckStandalone(s)
+	if len(errs) != 2 || errs[0].Field != "secret" || errs[1].Rule != "dive" {
+		t.Errorf("expected errors on secret and the dive, got %v", errs)
+	}
+}
+
+func TestCheckGroups(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []vtypes.ValidationField
+		want   string
+	}{
+		{
+			name: "distinct groups",
+			fields: []vtypes.ValidationField{
+				{Name: "Name", Groups: map[string][]string{"required": {"create", "bulk_update"}}},
+				{Name: "Age", Groups: map[string][]string{"min": {"create"}}},
+			},
+		},
+		{
+			name: "same method across fields",
+			fields: []vtypes.ValidationField{
+				{Name: "Name", Groups: map[string][]string{"required": {"update1"}}},
+				{Name: "Age", Groups: map[string][]string{"min": {"update_1"}}},
+			},
+			want: "groups 'update1' and 'update_1' both generate ValidateUpdate1",
+		},
+		{
+			name: "same method from omitempty",
+			fields: []vtypes.ValidationField{
+				{Name: "Name", Groups: map[string][]string{"required": {"update1"}}, OmitIn: []string{"update_1"}},
+			},
+			want: "groups 'update_1' and 'update1' both generate ValidateUpdate1",
+		},
+		{
+			name: "uppercase",
+			fields: []vtypes.ValidationField{
+				{Name: "Name", Groups: map[string][]string{"required": {"bulkUpdate"}}},
+			},
+			want: "group 'bulkUpdate' must be lowercase letters and digits, optionally separated by underscores",
+		},
+		{
+			name: "reserved",
+			fields: []vtypes.ValidationField{
+				{Name: "Name", Groups: map[string][]string{"required": {"context"}}},
+			},
+			want: "group 'context' is reserved",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := New(nil).CheckGroups(vtypes.ValidationStruct{Name: "User", Fields: tt.fields}, nil)
+			if tt.want == "" {
+				if len(errs) != 0 {
+					t.Fatalf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Message != tt.want {
+				t.Errorf("got %v, want %q", errs, tt.want)
+			}
+		})
+	}
+}
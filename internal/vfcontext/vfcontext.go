@@ -5,16 +5,35 @@ import (
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
 
-type PackageOptions uint8
+// PackageOptions selects the optional helpers emitted into the support
+// package, one bit per helper file
+type PackageOptions uint16
 
 const (
-	EmailPO = 1 << iota
+	EmailPO PackageOptions = 1 << iota
+	URLPO
+	HostnamePO
+	IPPO
+	MACPO
+	PortPO
+	UUIDPO
+	ULIDPO
+	GraphemePO
+	DivePO
 )
 
+// File is a generated file waiting to be written or checked
+type File struct {
+	Path    string
+	Content string
+}
+
 type Context struct {
 	Config   vtypes.GenerationConfig
 	Registry interface {
 		GetRequiredImports(fields []vtypes.ValidationField) []string
+		RuleNames() []string
+		PackageOptions(structs []vtypes.ValidationStruct) PackageOptions
 		GetForTypeCheck(name string) (interface{ SupportsType(vtypes.FieldType) bool }, bool)
 		GetAllForGeneration() map[string]interface {
 			Generate(*builder.CodeBuilder, vtypes.ValidationField, string) error
@@ -23,6 +42,8 @@ type Context struct {
 	}
 	Structs        []vtypes.ValidationStruct
 	Output         string
+	SupportFiles   []File
+	StaleFiles     []string // Support files no longer needed, reported in check mode
 	Errors         vtypes.CompilerErrors
 	PackageOptions PackageOptions
 }
@@ -1,6 +1,10 @@
 package vtypes
 
-import "go/types"
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
 
 // FieldType represents the type information for a struct field
 type FieldType struct {
@@ -8,7 +12,9 @@ type FieldType struct {
 	Kind       TypeKind   // Simplified type classification
 	IsPointer  bool       // Whether it's a pointer type
 	IsSlice    bool       // Whether it's a slice type
-	Underlying types.Type // Underlying type for pointers/slices
+	IsMap      bool       // Whether it's a map type; Kind describes its values
+	IsElemPtr  bool       // Whether slice or map elements are pointers to structs
+	Underlying types.Type // Underlying type for pointers/slices/maps
 }
 
 type TypeKind int
@@ -69,21 +75,84 @@ func (tk TypeKind) String() string {
 	}
 }
 
+// String returns the type as written, e.g. *bool or []string
+func (ft FieldType) String() string {
+	elem := ft.Kind.String()
+	if ft.IsElemPtr {
+		elem = "*" + elem
+	}
+
+	switch {
+	case ft.IsPointer:
+		return "*" + elem
+	case ft.IsSlice:
+		return "[]" + elem
+	case ft.IsMap:
+		return "map[...]" + elem
+	}
+	return elem
+}
+
+// NonZero returns a condition that is true when expr, of this type, holds a
+// non-zero value, and false when the type has no simple zero check
+func (ft FieldType) NonZero(expr string) (string, bool) {
+	switch {
+	case ft.IsSlice || ft.IsMap:
+		return fmt.Sprintf("len(%s) != 0", expr), true
+	case ft.IsPointer:
+		return expr + " != nil", true
+	}
+
+	switch ft.Kind {
+	case TypeString:
+		return expr + ` != ""`, true
+	case TypeInt, TypeInt8, TypeInt16, TypeInt32, TypeInt64,
+		TypeUint, TypeUint8, TypeUint16, TypeUint32, TypeUint64,
+		TypeFloat32, TypeFloat64:
+		return expr + " != 0", true
+	case TypeBool:
+		return expr, true
+	}
+	return "", false
+}
+
 // ValidationField represents a field with validation rules and type info
 type ValidationField struct {
-	Name     string
-	Type     FieldType
-	JSONName string
-	Rules    map[string]string
+	Name      string
+	Type      FieldType
+	JSONName  string // Name errors are reported under, see GenerationConfig.NameFrom
+	Rules     map[string]string
+	Groups    map[string][]string // Validation groups limiting a rule, by rule; rules not listed always apply
+	Messages  map[string]string   // Message template overrides keyed by message code
+	Sensitive bool                // Value is redacted from validation errors
+	OmitEmpty bool                // Skip the rules when the value is zero
+	OmitIn    []string            // Groups OmitEmpty is limited to, all when empty
 }
 
 // ValidationStruct represents a struct with validation
 type ValidationStruct struct {
-	Name        string
-	PackageName string
-	Fields      []ValidationField
+	Name            string
+	PackageName     string
+	Fields          []ValidationField
+	PointerReceiver bool // Generate Validate on *T rather than T
+}
+
+// GroupMethodName turns a group such as bulk_update into BulkUpdate, the
+// suffix of its generated ValidateBulkUpdate method
+func GroupMethodName(group string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(group, "_") {
+		if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
 }
 
+// SupportAlias is the name generated code imports the supporting package
+// under, whatever the package is called
+const SupportAlias = "valgen"
+
 // GenerationConfig holds configuration for code generation
 type GenerationConfig struct {
 	InputFile           string
@@ -95,6 +164,23 @@ type GenerationConfig struct {
 	ModuleName          string
 	ProjectRoot         string // Project root directory
 	Version             string
+	Check               bool              // Compare against existing files instead of writing them
+	OutputPattern       string            // Output file name pattern, e.g. "{name}_validation.gen.go"
+	TagName             string            // Struct tag holding validation rules (default: "validate")
+	NameFrom            string            // Tag naming fields in errors, or "go" for the field name (default: "json")
+	Messages            map[string]string // Message templates keyed by message code
+	ProjectMessages     map[string]string // Project-wide message templates, for the shared English catalog
+	EnabledRules        []string          // Rules available to tags, all when empty
+	GRPC                bool              // Emit gRPC status conversion in the support package
+	RulesFile           string            // Sidecar file declaring rules outside struct tags
+	StandaloneDir       string            // Emit ValidateT functions into this directory instead of methods
+	OutputPackage       string            // Package of the generated file when it differs from PackageName
+	TargetImportPath    string            // Import path of the validated package in standalone mode
+	Receiver            string            // Validate receiver kind: "value" (default), "pointer" or "auto"
+	Context             bool              // Also emit ValidateContext(ctx), which Validate delegates to
+	Groups              []string          // Known validation groups, any valid name when empty
+	Length              string            // What string length rules count: "runes" (default), "bytes" or "graphemes"
+	ProjectLength       string            // Length mode of the config file, whose unit the shared catalog names
 }
 
 // CompilerError represents an error during compilation
@@ -114,8 +200,19 @@ const (
 	ErrorTypeMissing
 	ErrorTypeInvalid
 	ErrorTypeDuplicate
+	ErrorTypeInternal
 )
 
+// InternalError reports generated code for path that failed to format,
+// which means a rule emitted invalid Go
+func InternalError(path string, err error) CompilerError {
+	return CompilerError{
+		Type:     ErrorTypeInternal,
+		Message:  fmt.Sprintf("internal error: generated code for %s is not valid Go: %v", path, err),
+		Position: path,
+	}
+}
+
 func (e CompilerError) Error() string {
 	return e.Message
 }
@@ -1,18 +1,27 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/richardbowden/valforge/internal/configfile"
 	"github.com/richardbowden/valforge/internal/pipeline"
+	"github.com/richardbowden/valforge/internal/project"
 	"github.com/richardbowden/valforge/internal/rules"
+	"github.com/richardbowden/valforge/internal/sidecar"
 	"github.com/richardbowden/valforge/internal/vfcontext"
 	"github.com/richardbowden/valforge/internal/vtypes"
 )
 
-var showVersion bool
+var (
+	showVersion bool
+	configPath  string
+)
 
 type Stage interface {
 	Name() string
@@ -42,19 +51,13 @@ func (p *Pipeline) Execute(ctx *vfcontext.Context) error {
 	return nil
 }
 
-func setupRegistry() *rules.Registry {
+func setupRegistry(config vtypes.GenerationConfig) *rules.Registry {
 	registry := rules.NewRegistry()
-	registry.Register(&rules.RequiredRule{})
-	registry.Register(&rules.GreaterThanRule{})
-	registry.Register(&rules.LessThanRule{})
-	registry.Register(&rules.EqualFieldRule{})
-
-	registry.Register(&rules.MinLenRule{})
-	registry.Register(&rules.MaxLenRule{})
-	registry.Register(&rules.LenRule{})
-	registry.Register(&rules.EqualFieldSecureRule{})
+	rules.RegisterBuiltins(registry, config)
 
-	registry.Register(&rules.EmailRule{})
+	if len(config.EnabledRules) > 0 {
+		registry.Restrict(config.EnabledRules)
+	}
 	return registry
 }
 
@@ -66,14 +69,38 @@ func main() {
 		os.Exit(0)
 	}
 
-	registry := setupRegistry()
+	if err := loadConfigFile(&config); err != nil {
+		log.Fatal(err)
+	}
+
+	if !rules.ValidLength(config.Length) {
+		log.Fatalf("unknown length mode '%s', expected runes, bytes or graphemes", config.Length)
+	}
+	if !rules.ValidLength(config.ProjectLength) {
+		log.Fatalf("unknown length mode '%s' in the config file, expected runes, bytes or graphemes", config.ProjectLength)
+	}
+	// Every package shares the message catalog in the support package, so
+	// one package counting bytes would change the messages of the others
+	if !rules.SameUnit(config.Length, config.ProjectLength) {
+		project := config.ProjectLength
+		if project == "" {
+			project = rules.LengthRunes
+		}
+		log.Fatalf("-length %s conflicts with the project length mode %s: the support package's messages are shared by every package, so set length in the config file", config.Length, project)
+	}
+
+	registry := setupRegistry(config)
 	pipe := New()
 
 	pipe.AddStage(&pipeline.ParseStage{})
 	pipe.AddStage(&pipeline.TypeCheckStage{})
 	pipe.AddStage(&pipeline.ValforgePackageStage{})
 	pipe.AddStage(&pipeline.GenerateStage{})
-	pipe.AddStage(&pipeline.WriteStage{})
+	if config.Check {
+		pipe.AddStage(&pipeline.CheckStage{})
+	} else {
+		pipe.AddStage(&pipeline.WriteStage{})
+	}
 
 	ctx := &vfcontext.Context{
 		Config:   config,
@@ -81,11 +108,26 @@ func main() {
 	}
 
 	if err := pipe.Execute(ctx); err != nil {
+		var compilerErrors vtypes.CompilerErrors
+		if errors.As(err, &compilerErrors) {
+			for _, e := range compilerErrors {
+				if e.Field != "" {
+					fmt.Fprintf(os.Stderr, "%s.%s: %s\n", e.Struct, e.Field, e.Message)
+				} else {
+					fmt.Fprintln(os.Stderr, e.Message)
+				}
+			}
+		}
 		log.Fatal(err)
 	}
 
+	if config.Check {
+		fmt.Printf("✓ Generated validation for %d structs is up to date\n", len(ctx.Structs))
+		return
+	}
+
 	fmt.Printf("✓ Generated validation for %d structs in %s\n",
-		len(ctx.Structs), config.OutputFile)
+		len(ctx.Structs), ctx.Config.OutputFile)
 }
 
 func parseFlags() vtypes.GenerationConfig {
@@ -96,9 +138,120 @@ func parseFlags() vtypes.GenerationConfig {
 	flag.StringVar(&config.OutputFile, "output", "", "Output file")
 	flag.StringVar(&config.ValforgePackage, "valforge-package", "valgen", "Name of the valfore supporting code package")
 	flag.StringVar(&config.ValforgePackagePath, "valforge-path", "", "Path to error package (default: internal/valgen)")
+	flag.StringVar(&config.RulesFile, "rules", "", "Sidecar file declaring rules for structs, e.g. protobuf messages")
+	flag.StringVar(&config.StandaloneDir, "standalone", "", "Generate ValidateT functions into this directory, for types declared in other packages")
+	flag.StringVar(&config.TargetImportPath, "import-path", "", "Import path of the scanned package in standalone mode (default: from its go.mod)")
+	flag.StringVar(&config.Receiver, "receiver", "", "Receiver of generated Validate methods: value, pointer, or auto to follow the type's other methods (default: value)")
+	flag.BoolVar(&config.Context, "context", false, "Also generate ValidateContext(ctx context.Context), which Validate calls with context.Background()")
+	flag.Func("groups", "Comma separated validation groups tags may use, e.g. create,update (default: any)", func(value string) error {
+		config.Groups = nil
+		for _, group := range strings.Split(value, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				config.Groups = append(config.Groups, group)
+			}
+		}
+		return nil
+	})
+	flag.StringVar(&config.Length, "length", "", "What minlen, maxlen and len count: runes, bytes, or graphemes for user-perceived characters (default: runes)")
+	flag.StringVar(&config.TagName, "tag", "", "Struct tag to read validation rules from (default: validate)")
+	flag.StringVar(&config.NameFrom, "name-from", "", "Tag used to name fields in errors, e.g. json, yaml, form, query, or go for the Go field name (default: json)")
+	flag.BoolVar(&config.Check, "check", false, "Verify generated files are up to date without writing them")
+	flag.StringVar(&configPath, "config", "", "Config file (default: valforge.yaml or valforge.toml next to go.mod)")
 	flag.BoolVar(&showVersion, "version", false, "shows version then exits")
 	flag.Parse()
 
 	config.Version = GetVersion()
 	return config
 }
+
+// loadConfigFile applies the project config file to every setting that was
+// not given on the command line, and finds the project rules file
+func loadConfigFile(config *vtypes.GenerationConfig) error {
+	dir := "."
+	if config.StandaloneDir != "" {
+		// Standalone validators take settings from the project they are written to
+		dir = config.StandaloneDir
+	} else if config.InputFile != "" {
+		dir = filepath.Dir(config.InputFile)
+	} else if config.PackagePath != "" {
+		dir = config.PackagePath
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	root, _, err := project.FindProjectRoot(dir)
+	if err != nil {
+		return err
+	}
+
+	path := configPath
+	if path == "" {
+		path = configfile.Find(root)
+	}
+
+	if path != "" {
+		file, err := configfile.Load(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			rel = "."
+		}
+		applyConfigFile(config, file, rel)
+	}
+
+	if config.RulesFile == "" {
+		config.RulesFile = sidecar.Find(root)
+	}
+
+	return nil
+}
+
+// applyConfigFile copies settings for the package in dir, relative to the
+// project root, from the config file unless set by a flag
+func applyConfigFile(config *vtypes.GenerationConfig, file *configfile.File, dir string) {
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	if file.SupportPackage != "" && !explicit["valforge-package"] {
+		config.ValforgePackage = file.SupportPackage
+	}
+	if file.SupportPath != "" && !explicit["valforge-path"] {
+		config.ValforgePackagePath = file.SupportPath
+	}
+	// The support package is shared, so gRPC is only set for the whole project
+	config.GRPC = file.GRPC
+	if file.Length != "" && !explicit["length"] {
+		config.Length = file.Length
+	}
+	config.ProjectLength = file.Length
+	if !explicit["context"] {
+		config.Context = file.Context
+	}
+	if file.RulesFile != "" && !explicit["rules"] {
+		config.RulesFile = file.RulesFile
+	}
+
+	settings := file.For(dir)
+	config.OutputPattern = settings.Output
+	if !explicit["tag"] {
+		config.TagName = settings.Tag
+	}
+	if !explicit["name-from"] {
+		config.NameFrom = settings.NameFrom
+	}
+	if !explicit["receiver"] {
+		config.Receiver = settings.Receiver
+	}
+	if !explicit["groups"] {
+		config.Groups = settings.Groups
+	}
+	config.Messages = settings.Messages
+	config.ProjectMessages = file.Messages
+	config.EnabledRules = settings.Rules
+}
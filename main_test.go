@@ -0,0 +1,360 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The end-to-end tests build valforge once, then run it the way go generate
+// would in scratch modules copied from testdata, and run go vet and go test
+// there against the generated code.
+
+// valforgeBin is the binary built by TestMain
+var valforgeBin string
+
+// testModule is the module path of the scratch modules, so generated code
+// imports the support package as testModule/internal/valgen
+const testModule = "example.com/app"
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "valforge-e2e")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	valforgeBin = filepath.Join(dir, "valforge")
+	if out, err := exec.Command("go", "build", "-o", valforgeBin, ".").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build valforge: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// newModule copies testdata/fixture into a fresh module and returns its
+// directory. The module is testModule unless the fixture has a go.mod.
+func newModule(t *testing.T, fixture string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("end-to-end test")
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join("testdata", fixture)
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dir, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fixtures with dependencies bring their own go.mod
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); os.IsNotExist(err) {
+		gomod := fmt.Sprintf("module %s\n\ngo 1.25\n", testModule)
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// runValforge runs valforge in dir and returns its combined output
+func runValforge(dir string, args ...string) (string, error) {
+	cmd := exec.Command(valforgeBin, args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// generate runs valforge in dir, failing t when it does not succeed
+func generate(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := runValforge(dir, args...)
+	if err != nil {
+		t.Fatalf("valforge %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return out
+}
+
+// tidy resolves the modules pinned by a fixture's go.mod. They come from the
+// module cache or the proxy, and t is skipped when neither is available.
+func tidy(t *testing.T, dir string) {
+	t.Helper()
+	cmd := exec.Command("go", "mod", "tidy")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("fixture modules unavailable: %v\n%s", err, out)
+	}
+}
+
+// goTest vets and tests the module in dir, so the fixture's own tests check
+// the behaviour of the generated code, and checks the code is gofmt clean
+func goTest(t *testing.T, dir string) {
+	t.Helper()
+
+	cmd := exec.Command("gofmt", "-l", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil || len(out) > 0 {
+		t.Fatalf("gofmt -l: %v\n%s", err, out)
+	}
+
+	for _, args := range [][]string{{"vet", "./..."}, {"test", "./..."}} {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+}
+
+func TestLength_Bytes(t *testing.T) {
+	dir := newModule(t, "length_bytes")
+	generate(t, dir, "-file", "models.go")
+	goTest(t, dir)
+}
+
+func TestLength_ConflictingFlag(t *testing.T) {
+	// The support package's messages follow the project length mode, runes
+	// without a config file, so a package cannot count bytes on its own
+	dir := newModule(t, "basic")
+	out, err := runValforge(dir, "-file", "models.go", "-length", "bytes")
+	if err == nil || !strings.Contains(out, "conflicts with the project length mode runes") {
+		t.Errorf("got %v, want a conflict error:\n%s", err, out)
+	}
+
+	dir = newModule(t, "length_bytes")
+	if out, err := runValforge(dir, "-file", "models.go", "-length", "runes"); err == nil {
+		t.Errorf("-length runes accepted with length: bytes in the config file:\n%s", out)
+	}
+}
+
+func TestLength_Graphemes(t *testing.T) {
+	dir := newModule(t, "length_graphemes")
+	generate(t, dir, "-file", "models.go", "-length", "graphemes")
+	goTest(t, dir)
+
+	if _, err := os.Stat(filepath.Join(dir, "internal", "valgen", "grapheme.gen.go")); err != nil {
+		t.Errorf("grapheme helper not written: %v", err)
+	}
+}
+
+func TestWrite_UnchangedFilesKeepMtime(t *testing.T) {
+	dir := newModule(t, "basic")
+	generate(t, dir, "-file", "models.go")
+	goTest(t, dir)
+
+	// Age every generated file so a rewrite within the same second shows
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	files := generatedFiles(t, dir)
+	for _, path := range files {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	generate(t, dir, "-file", "models.go")
+
+	after := generatedFiles(t, dir)
+	if len(after) != len(files) {
+		t.Errorf("got files %v after the second run, want %v", after, files)
+	}
+	for _, path := range after {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.ModTime().Equal(old) {
+			t.Errorf("%s was rewritten", path)
+		}
+	}
+}
+
+// generatedFiles lists the generated files under dir, and any temporary
+// files a write left behind
+func generatedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if strings.HasSuffix(path, ".gen.go") || strings.HasSuffix(path, ".tmp") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestConfigFile_FlagsTakePrecedence(t *testing.T) {
+	dir := newModule(t, "config")
+	generate(t, dir, "-file", "models.go", "-name-from", "json")
+	generate(t, dir, "-file", filepath.Join("forms", "login.go"))
+
+	for _, name := range []string{"models_checks.gen.go", "forms/login_checks.gen.go", "pkg/checks/errors.gen.go"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("config file output not used: %v", err)
+		}
+	}
+
+	goTest(t, dir)
+
+	// The packages share the support package, so checking either one after
+	// generating both finds it up to date
+	generate(t, dir, "-file", "models.go", "-name-from", "json", "-check")
+	generate(t, dir, "-file", filepath.Join("forms", "login.go"), "-check")
+}
+
+func TestSupportPath_OutsideProject(t *testing.T) {
+	dir := newModule(t, "basic")
+	out, err := runValforge(dir, "-file", "models.go", "-valforge-path", filepath.Join("..", "valgen"))
+	if err == nil || !strings.Contains(out, "outside the project") {
+		t.Errorf("got %v, want an error for a support package outside the project:\n%s", err, out)
+	}
+}
+
+func TestGRPC_Status(t *testing.T) {
+	dir := newModule(t, "grpc")
+	generate(t, dir, "-file", "models.go")
+
+	// gRPC is a project setting, so generating another package keeps the
+	// conversion the first one relies on
+	generate(t, dir, "-file", filepath.Join("admin", "role.go"))
+	grpcFile := filepath.Join(dir, "internal", "valgen", "grpc.gen.go")
+	if _, err := os.Stat(grpcFile); err != nil {
+		t.Fatalf("gRPC conversion removed by another package: %v", err)
+	}
+
+	tidy(t, dir)
+	goTest(t, dir)
+
+	// Turning gRPC off in the config file removes it
+	if err := os.WriteFile(filepath.Join(dir, "valforge.yaml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	generate(t, dir, "-file", filepath.Join("admin", "role.go"))
+	if _, err := os.Stat(grpcFile); !os.IsNotExist(err) {
+		t.Errorf("gRPC conversion kept after turning it off: %v", err)
+	}
+}
+
+func TestProtobuf_SidecarRules(t *testing.T) {
+	dir := newModule(t, "protobuf")
+	generate(t, dir, "-file", filepath.Join("api", "user.pb.go"), "-rules", filepath.Join("api", "rules.yaml"))
+
+	if _, err := os.Stat(filepath.Join(dir, "api", "user.pb_validation.gen.go")); err != nil {
+		t.Errorf("validation not written next to the message: %v", err)
+	}
+
+	// go vet reports copied locks, so it also checks Validate takes a pointer
+	tidy(t, dir)
+	goTest(t, dir)
+}
+
+func TestStandalone_ValidateFunctions(t *testing.T) {
+	dir := newModule(t, "standalone")
+	generate(t, dir, "-package", "models", "-standalone", filepath.Join("internal", "modelval"))
+
+	if _, err := os.Stat(filepath.Join(dir, "models", "models_validation.gen.go")); err == nil {
+		t.Error("standalone mode wrote methods into the scanned package")
+	}
+
+	goTest(t, dir)
+}
+
+func TestContext_ValidateContext(t *testing.T) {
+	dir := newModule(t, "context")
+	generate(t, dir, "-file", "models.go", "-context")
+	goTest(t, dir)
+}
+
+func TestCheck_RoundTrip(t *testing.T) {
+	dir := newModule(t, "basic")
+	generate(t, dir, "-file", "models.go")
+	generate(t, dir, "-file", "models.go", "-check")
+
+	// Tighten a rule and drop the only email rule, so the output changes and
+	// the email helper becomes stale
+	models := filepath.Join(dir, "models.go")
+	src, err := os.ReadFile(models)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(src), "maxlen=20", "maxlen=30", 1)
+	edited = strings.Replace(edited, `validate:"email"`, `validate:"required"`, 1)
+	if err := os.WriteFile(models, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "models_validation.gen.go")
+	before, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runValforge(dir, "-file", "models.go", "-check")
+	if err == nil {
+		t.Fatalf("check passed on stale files:\n%s", out)
+	}
+	for _, want := range []string{
+		"--- a/models_validation.gen.go\n+++ b/models_validation.gen.go\n",
+		"-\tif utf8.RuneCountInString(v.Name) > 20 {\n+\tif utf8.RuneCountInString(v.Name) > 30 {\n",
+		"--- a/internal/valgen/emailvalidation.gen.go\n+++ /dev/null\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("check output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, dir) {
+		t.Errorf("check output has absolute paths:\n%s", out)
+	}
+
+	after, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Error("check mode rewrote the output")
+	}
+
+	generate(t, dir, "-file", "models.go")
+	generate(t, dir, "-file", "models.go", "-check")
+
+	// A missing file is diffed as created, so patch and git apply accept it
+	if err := os.Remove(output); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runValforge(dir, "-file", "models.go", "-check")
+	if err == nil {
+		t.Fatalf("check passed with the output missing:\n%s", out)
+	}
+	if want := "--- /dev/null\n+++ b/models_validation.gen.go\n"; !strings.Contains(out, want) {
+		t.Errorf("check output lacks %q:\n%s", want, out)
+	}
+}
@@ -0,0 +1,9 @@
+package app
+
+// User is the fixture shared by end-to-end tests of the write and check
+// modes and the configuration
+type User struct {
+	Name  string `json:"name" validate:"required,maxlen=20"`
+	Email string `json:"email" validate:"email"`
+	Age   int    `json:"age" validate:"gte=18"`
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/app/internal/valgen"
+)
+
+func TestUser_Validate(t *testing.T) {
+	if err := (User{Name: "Zoe", Email: "zoe@example.com", Age: 30}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var verr *valgen.ValidationError
+	if err := (User{Age: 12}).Validate(); !errors.As(err, &verr) || len(verr.Errors) != 3 {
+		t.Fatalf("expected three errors, got %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+package forms
+
+// Login reads its rules from the vf tag and names fields by their form tag,
+// as the forms package overrides in valforge.yaml
+type Login struct {
+	Email string `json:"email" form:"email_address" vf:"required"`
+}
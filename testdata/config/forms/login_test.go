@@ -0,0 +1,23 @@
+package forms
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/app/pkg/checks"
+)
+
+func TestLogin_PackageOverrides(t *testing.T) {
+	var verr *checks.ValidationError
+	if err := (Login{}).Validate(); !errors.As(err, &verr) || len(verr.Errors) != 1 {
+		t.Fatalf("expected one error, got %v", err)
+	}
+
+	fe := verr.Errors[0]
+	if fe.Field != "email_address" {
+		t.Errorf("got field %q, want %q", fe.Field, "email_address")
+	}
+	if got, want := fe.Message, "email_address must be provided"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,7 @@
+package app
+
+// Member is generated with the project settings of valforge.yaml, except
+// the field names given as a flag
+type Member struct {
+	Name string `json:"name" validate:"required,maxlen=4"`
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/app/pkg/checks"
+)
+
+func TestMember_ProjectMessage(t *testing.T) {
+	var verr *checks.ValidationError
+	if err := (Member{}).Validate(); !errors.As(err, &verr) {
+		t.Fatalf("expected *checks.ValidationError, got %v", err)
+	}
+
+	// -name-from json overrides name_from: go in the config file
+	fe := verr.Errors[0]
+	if fe.Field != "name" {
+		t.Errorf("got field %q, want %q", fe.Field, "name")
+	}
+	if got, want := fe.Message, "name must be provided"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMember_ProjectLength(t *testing.T) {
+	// Four runes but five bytes, so it fails as length: bytes in the config
+	// file applies
+	var verr *checks.ValidationError
+	if err := (Member{Name: "Zoëy"}).Validate(); !errors.As(err, &verr) {
+		t.Fatalf("expected *checks.ValidationError, got %v", err)
+	}
+	if got, want := verr.Errors[0].Message, "name must be at most 4 bytes"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
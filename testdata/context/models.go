@@ -0,0 +1,25 @@
+package app
+
+// Order is generated with -context
+type Order struct {
+	ID   int    `json:"id" validate:"gt=0@update"`
+	Note string `json:"note" validate:"required,maxlen=5"`
+}
+
+// Tag has no validation groups
+type Tag struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// Batch dives into nested values, passing the context on
+type Batch struct {
+	Owner *Tag           `json:"owner" validate:"dive"`
+	Tags  map[string]Tag `json:"tags" validate:"dive"`
+	Steps []Step         `json:"steps" validate:"dive"`
+	Extra []*Order       `json:"extra" validate:"omitempty,dive"`
+}
+
+// Step is validated by a hand-written ValidateContext
+type Step struct {
+	Name string
+}
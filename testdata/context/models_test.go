@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"example.com/app/internal/valgen"
+)
+
+func TestValidateContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Valid or not, a cancelled context stops validation with ctx.Err()
+	for _, err := range []error{
+		Order{Note: "ok"}.ValidateContext(ctx),
+		Order{}.ValidateContext(ctx),
+		Order{}.ValidateGroupsContext(ctx, "update"),
+		Tag{}.ValidateContext(ctx),
+	} {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+	}
+}
+
+func TestValidateContext_DeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	if err := (Tag{}).ValidateContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestValidateContext_Live(t *testing.T) {
+	ctx := context.Background()
+
+	if err := (Order{Note: "ok"}).ValidateContext(ctx); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var verr *valgen.ValidationError
+	if err := (Order{Note: "too long"}).ValidateGroupsContext(ctx, "update"); !errors.As(err, &verr) || len(verr.Errors) != 2 {
+		t.Errorf("expected errors on id and note, got %v", err)
+	}
+
+	// Validate runs with context.Background()
+	if err := (Tag{}).Validate(); !errors.As(err, &verr) {
+		t.Errorf("expected *valgen.ValidationError, got %v", err)
+	}
+}
+
+// stepKey is a context key whose value Step.ValidateContext records
+type stepKey struct{}
+
+// onStep is called by Step.ValidateContext with its context
+var onStep = func(ctx context.Context) {}
+
+func (s Step) ValidateContext(ctx context.Context) error {
+	onStep(ctx)
+	return nil
+}
+
+func TestDive_PassesContext(t *testing.T) {
+	var got []any
+	onStep = func(ctx context.Context) { got = append(got, ctx.Value(stepKey{})) }
+	t.Cleanup(func() { onStep = func(context.Context) {} })
+
+	ctx := context.WithValue(context.Background(), stepKey{}, "request")
+	b := Batch{
+		Owner: &Tag{},
+		Tags:  map[string]Tag{"a": {Name: "ok"}, "b": {}},
+		Steps: make([]Step, 2),
+		Extra: []*Order{nil, {ID: 0}},
+	}
+
+	var verr *valgen.ValidationError
+	if err := b.ValidateContext(ctx); !errors.As(err, &verr) {
+		t.Fatalf("expected *valgen.ValidationError, got %v", err)
+	}
+
+	var paths []string
+	for _, fe := range verr.Errors {
+		paths = append(paths, fe.Path)
+	}
+	want := []string{"owner.name", "tags[b].name", "extra[1].note"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("got paths %v, want %v", paths, want)
+	}
+
+	if !slices.Equal(got, []any{"request", "request"}) {
+		t.Errorf("steps validated with %v, want the caller's context twice", got)
+	}
+}
+
+func TestDive_CancelledMidSlice(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	onStep = func(context.Context) {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+	}
+	t.Cleanup(func() { onStep = func(context.Context) {} })
+
+	// The second step cancels, so the third is never validated and the
+	// cancellation is returned rather than recorded against a step
+	err := (Batch{Steps: make([]Step, 5)}).ValidateContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if calls != 2 {
+		t.Errorf("validated %d steps, want 2", calls)
+	}
+}
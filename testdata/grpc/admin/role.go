@@ -0,0 +1,6 @@
+package admin
+
+// Role lives in another package, whose runs share grpc.gen.go
+type Role struct {
+	Name string `json:"name" validate:"required"`
+}
@@ -0,0 +1,7 @@
+package app
+
+// User is returned from gRPC handlers as a status error
+type User struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"email"`
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"example.com/app/internal/valgen"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUser_GRPCStatus(t *testing.T) {
+	err := User{Email: "not an email"}.Validate()
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected an InvalidArgument status, got %v", err)
+	}
+
+	var violations []string
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				violations = append(violations, v.GetField()+": "+v.GetDescription())
+			}
+		}
+	}
+
+	var verr *valgen.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *valgen.ValidationError, got %v", err)
+	}
+	var want []string
+	for _, fe := range verr.Errors {
+		want = append(want, fe.Path+": "+fe.Message)
+	}
+	if len(want) != 2 || !slices.Equal(violations, want) {
+		t.Errorf("got violations %q, want %q", violations, want)
+	}
+}
@@ -0,0 +1,8 @@
+package app
+
+// Account limits its fields to the size of their database columns
+type Account struct {
+	Login string `json:"login" validate:"maxlen=4"`
+	Code  string `json:"code" validate:"omitempty,len=2"`
+	Notes string `json:"notes" validate:"omitempty,minlen=3" vmsg:"minlen=Write at least {min} bytes"`
+}
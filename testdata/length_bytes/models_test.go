@@ -0,0 +1,75 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/app/internal/valgen"
+)
+
+func TestAccount_Bytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		account Account
+		field   string
+	}{
+		{name: "ascii", account: Account{Login: "zoey", Code: "de"}},
+		{name: "two byte rune", account: Account{Login: "Zoë"}},
+		{name: "runes fit but bytes do not", account: Account{Login: "Zoëy"}, field: "login"},
+		{name: "one rune of two bytes", account: Account{Code: "ë"}},
+		{name: "one byte short", account: Account{Code: "e"}, field: "code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.account.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var verr *valgen.ValidationError
+			if !errors.As(err, &verr) || len(verr.Errors) != 1 || verr.Errors[0].Field != tt.field {
+				t.Fatalf("expected one error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestAccount_BytesMessages(t *testing.T) {
+	verr := Account{Login: "Zoëy", Code: "e", Notes: "ab"}.Validate().(*valgen.ValidationError)
+
+	tests := []struct {
+		lang  string
+		field string
+		want  string
+	}{
+		{lang: "en", field: "login", want: "login must be at most 4 bytes"},
+		{lang: "en", field: "code", want: "code must be exactly 2 bytes"},
+		{lang: "en", field: "notes", want: "Write at least 3 bytes"},
+		{lang: "de", field: "login", want: "login darf höchstens 4 Bytes lang sein"},
+		{lang: "de", field: "code", want: "code muss genau 2 Bytes lang sein"},
+		{lang: "de", field: "notes", want: "Write at least 3 bytes"},
+		{lang: "ja", field: "login", want: "loginは4バイト以内でなければなりません"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.field, func(t *testing.T) {
+			errs := verr.Errors
+			if tt.lang != "en" {
+				errs = verr.Localize(valgen.TranslatorFor(tt.lang)).Errors
+			}
+			for _, fe := range errs {
+				if fe.Field == tt.field {
+					if fe.Message != tt.want {
+						t.Errorf("got %q, want %q", fe.Message, tt.want)
+					}
+					return
+				}
+			}
+			t.Errorf("no error on %s", tt.field)
+		})
+	}
+}
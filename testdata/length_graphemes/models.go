@@ -0,0 +1,7 @@
+package app
+
+// Badge holds short user-perceived strings such as a flag or an emoji
+type Badge struct {
+	Icon  string `json:"icon" validate:"len=1"`
+	Label string `json:"label" validate:"omitempty,maxlen=3"`
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/app/internal/valgen"
+)
+
+func TestBadge_Graphemes(t *testing.T) {
+	tests := []struct {
+		name  string
+		badge Badge
+		field string
+	}{
+		{name: "ascii", badge: Badge{Icon: "x", Label: "abc"}},
+		{name: "flag", badge: Badge{Icon: "\U0001F1E9\U0001F1EA"}},
+		{name: "zwj family", badge: Badge{Icon: "\U0001F468\u200d\U0001F469\u200d\U0001F467"}},
+		{name: "skin tone", badge: Badge{Icon: "\U0001F44D\U0001F3FD"}},
+		{name: "combining mark", badge: Badge{Icon: "e\u0301"}},
+		{name: "three flags", badge: Badge{Icon: "x", Label: "\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7\U0001F1EF\U0001F1F5"}},
+		{name: "two flags", badge: Badge{Icon: "\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7"}, field: "icon"},
+		{name: "two letters", badge: Badge{Icon: "ab"}, field: "icon"},
+		{name: "four graphemes", badge: Badge{Icon: "x", Label: "Zoëy"}, field: "label"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.badge.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var verr *valgen.ValidationError
+			if !errors.As(err, &verr) || len(verr.Errors) != 1 || verr.Errors[0].Field != tt.field {
+				t.Fatalf("expected one error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestBadge_GraphemesMessage(t *testing.T) {
+	verr := Badge{Icon: "ab"}.Validate().(*valgen.ValidationError)
+
+	if got, want := verr.Errors[0].Message, "icon must be exactly 1 characters"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
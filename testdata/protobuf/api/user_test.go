@@ -0,0 +1,31 @@
+package api
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"example.com/app/internal/valgen"
+)
+
+func TestCreateUserRequest_SidecarRules(t *testing.T) {
+	if err := (&CreateUserRequest{Email: "zoe@example.com", DisplayName: "Zoe", Age: 30}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := (&CreateUserRequest{Email: "zoe", DisplayName: "Zoe Example", Age: 12}).Validate()
+
+	var verr *valgen.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *valgen.ValidationError, got %v", err)
+	}
+
+	var got []string
+	for _, fe := range verr.Errors {
+		got = append(got, fe.Field+":"+fe.Rule)
+	}
+	slices.Sort(got)
+	if want := []string{"age:gte", "display_name:maxlen", "email:email"}; !slices.Equal(got, want) {
+		t.Errorf("got errors %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,20 @@
+package modelval
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/app/internal/valgen"
+	"example.com/app/models"
+)
+
+func TestValidateUser(t *testing.T) {
+	if err := ValidateUser(&models.User{Email: "zoe@example.com", Age: 30}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var verr *valgen.ValidationError
+	if err := ValidateUser(&models.User{Email: "zoe", Age: 12}); !errors.As(err, &verr) || len(verr.Errors) != 2 {
+		t.Fatalf("expected two errors, got %v", err)
+	}
+}
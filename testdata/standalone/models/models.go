@@ -0,0 +1,8 @@
+package models
+
+// User stands in for a type of another module, which cannot be given tags
+// or methods, so its rules are declared in valforge.rules.yaml
+type User struct {
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"tests/internal/valgen"
+)
+
+func TestSignup_BoolRules(t *testing.T) {
+	no := false
+
+	if err := (Signup{Terms: true, Marketing: &no}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := Signup{Robot: true}.Validate()
+
+	var verr *valgen.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *valgen.ValidationError, got %v", err)
+	}
+
+	want := map[string]string{
+		"terms":     "istrue",
+		"robot":     "isfalse",
+		"marketing": "required",
+	}
+	if len(verr.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), verr.Errors)
+	}
+	for _, fe := range verr.Errors {
+		if want[fe.Field] != fe.Rule {
+			t.Errorf("%s: expected rule %s, got %s", fe.Field, want[fe.Field], fe.Rule)
+		}
+	}
+}
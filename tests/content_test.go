@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"tests/internal/valgen"
+)
+
+func TestCoupon_ContentRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		coupon Coupon
+		want   map[string]string
+	}{
+		{
+			name:   "valid",
+			coupon: Coupon{Code: "SAVE2024", Link: "https://example.com", Region: "emea"},
+		},
+		{
+			name:   "code classes",
+			coupon: Coupon{Code: "save-204", Link: "https://example.com"},
+			want:   map[string]string{"code": "alphanum"},
+		},
+		{
+			name:   "link with quoted parameter",
+			coupon: Coupon{Code: "SAVE2024", Link: `http://example.com/"<script>`},
+			want:   map[string]string{"link": "startswith"},
+		},
+		{
+			name:   "region letters only",
+			coupon: Coupon{Code: "SAVE2024", Link: "https://example.com", Region: "Zürich"},
+			want:   map[string]string{"region": "alpha"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.coupon.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var verr *valgen.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *valgen.ValidationError, got %v", err)
+			}
+			for _, fe := range verr.Errors {
+				if rule, ok := tt.want[fe.Field]; ok && rule == fe.Rule {
+					return
+				}
+			}
+			t.Errorf("expected %v among %v", tt.want, verr.Errors)
+		})
+	}
+}
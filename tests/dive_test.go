@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	valgen "tests/internal/valgen"
+)
+
+// onLine is called by OrderLine.ValidateContext
+var onLine = func(ctx context.Context, line OrderLine) error { return nil }
+
+func (l OrderLine) ValidateContext(ctx context.Context) error {
+	return onLine(ctx, l)
+}
+
+func TestTeam_Dive(t *testing.T) {
+	team := Team{
+		Lead:    &Nickname{Short: "toolong"},
+		Members: []Nickname{{Short: "ok"}, {Exact: "abc"}},
+		Roles:   map[string]Nickname{"admin": {Short: "abcd"}},
+	}
+
+	var verr *valgen.ValidationError
+	if err := team.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("expected *valgen.ValidationError, got %v", err)
+	}
+
+	var paths []string
+	for _, fe := range verr.Errors {
+		paths = append(paths, fe.Path)
+	}
+	want := []string{"lead.short", "members[1].exact", "roles[admin].short"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("got paths %v, want %v", paths, want)
+	}
+
+	// A nil lead and an empty map are skipped
+	if err := (Team{Members: []Nickname{{Short: "ok"}}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestOrder_CancelledMidSlice(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var validated []string
+	onLine = func(ctx context.Context, line OrderLine) error {
+		validated = append(validated, line.SKU)
+		if line.SKU == "b" {
+			cancel()
+			return ctx.Err()
+		}
+		return nil
+	}
+	t.Cleanup(func() { onLine = func(context.Context, OrderLine) error { return nil } })
+
+	order := Order{Lines: []OrderLine{{SKU: "a"}, {SKU: "b"}, {SKU: "c"}, {SKU: "d"}}}
+	if err := order.ValidateContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if !slices.Equal(validated, []string{"a", "b"}) {
+		t.Errorf("validated lines %v, want a and b only", validated)
+	}
+}
+
+func TestOrder_LineErrors(t *testing.T) {
+	onLine = func(ctx context.Context, line OrderLine) error {
+		if line.SKU == "" {
+			verr := valgen.NewValidationError("OrderLine")
+			verr.Add(valgen.FieldError{Field: "sku", Message: "sku is required"})
+			return verr
+		}
+		return nil
+	}
+	t.Cleanup(func() { onLine = func(context.Context, OrderLine) error { return nil } })
+
+	var verr *valgen.ValidationError
+	err := (Order{Lines: []OrderLine{{SKU: "a"}, {}}}).ValidateContext(context.Background())
+	if !errors.As(err, &verr) || len(verr.Errors) != 1 || verr.Errors[0].Path != "lines[1].sku" {
+		t.Errorf("expected one error at lines[1].sku, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"tests/internal/valgen"
+)
+
+func TestProfile_Groups(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		run     func(Profile) error
+		want    []string
+	}{
+		{
+			name: "ungrouped rules only",
+			run:  Profile.Validate,
+		},
+		{
+			name: "create requires handle",
+			run:  Profile.ValidateCreate,
+			want: []string{"handle"},
+		},
+		{
+			name:    "update requires id and limits bio",
+			profile: Profile{Bio: strings.Repeat("x", 161)},
+			run:     Profile.ValidateUpdate,
+			want:    []string{"id", "bio"},
+		},
+		{
+			name:    "groups combine",
+			profile: Profile{Handle: "ada"},
+			run: func(p Profile) error {
+				return p.ValidateGroups("create", "update")
+			},
+			want: []string{"id"},
+		},
+		{
+			name:    "omitempty checks set values",
+			profile: Profile{Site: "x"},
+			run:     Profile.Validate,
+			want:    []string{"site"},
+		},
+		{
+			name:    "escaped @ is part of the value",
+			profile: Profile{Team: "team@support desk"},
+			run:     Profile.Validate,
+		},
+		{
+			name:    "escaped @ must be matched",
+			profile: Profile{Team: "team support"},
+			run:     Profile.Validate,
+			want:    []string{"team"},
+		},
+		{
+			name:    "group after the rule name keeps the value literal",
+			profile: Profile{ID: 1, Domain: "example.org"},
+			run:     Profile.ValidateUpdate,
+			want:    []string{"domain"},
+		},
+		{
+			name:    "grouped literal rule skipped outside its group",
+			profile: Profile{Domain: "example.org"},
+			run:     Profile.Validate,
+		},
+		{
+			name:    "ungrouped rules always apply",
+			profile: Profile{ID: 1, Handle: strings.Repeat("x", 16)},
+			run:     Profile.ValidateCreate,
+			want:    []string{"handle"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run(tt.profile)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var verr *valgen.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *valgen.ValidationError, got %v", err)
+			}
+			if len(verr.Errors) != len(tt.want) {
+				t.Fatalf("expected %d errors, got %v", len(tt.want), verr.Errors)
+			}
+			for _, field := range tt.want {
+				if !verr.HasField(field) {
+					t.Errorf("expected an error for %s, got %v", field, verr.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestProfile_NoGroupFromValue(t *testing.T) {
+	// contains=team@@support must not create a "support" group
+	if _, ok := any(Profile{}).(interface{ ValidateSupport() error }); ok {
+		t.Error("Profile has ValidateSupport, the escaped @ was read as a group")
+	}
+}
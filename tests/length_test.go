@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"tests/internal/valgen"
+)
+
+func TestNickname_Runes(t *testing.T) {
+	tests := []struct {
+		name     string
+		nickname Nickname
+		field    string
+	}{
+		{name: "ascii", nickname: Nickname{Short: "Zoe"}},
+		{name: "multibyte rune counts once", nickname: Nickname{Short: "Zoë"}},
+		{name: "cjk", nickname: Nickname{Short: "日本語", Exact: "東京"}},
+		{name: "too long", nickname: Nickname{Short: "Zoëy"}, field: "short"},
+		{name: "combining mark counts apart", nickname: Nickname{Exact: "e\u0301"}},
+		{name: "flag is two runes", nickname: Nickname{Exact: "\U0001F1E9\U0001F1EA"}},
+		{name: "exact too short", nickname: Nickname{Exact: "ë"}, field: "exact"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.nickname.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var verr *valgen.ValidationError
+			if !errors.As(err, &verr) || len(verr.Errors) != 1 || verr.Errors[0].Field != tt.field {
+				t.Fatalf("expected one error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestNickname_RunesMessage(t *testing.T) {
+	verr := Nickname{Short: "Zoëy"}.Validate().(*valgen.ValidationError)
+
+	if got, want := verr.Errors[0].Message, "short must be at most 3 characters"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := verr.Localize(valgen.TranslatorFor("de")).Errors[0].Message, "short darf höchstens 3 Zeichen lang sein"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
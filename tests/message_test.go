@@ -0,0 +1,56 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"tests/internal/valgen"
+)
+
+func TestCredentials_MessageOverrides(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials Credentials
+		want        []string
+	}{
+		{
+			name:        "vmsg tag",
+			credentials: Credentials{},
+			want:        []string{"Enter a password", "Password too short, use 8+ characters"},
+		},
+		{
+			name:        "comment directive",
+			credentials: Credentials{Password: "long enough", Confirm: "long enougj"},
+			want:        []string{"Passwords do not match"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verr, ok := tt.credentials.Validate().(*valgen.ValidationError)
+			if !ok {
+				t.Fatalf("expected *valgen.ValidationError, got %v", verr)
+			}
+
+			var got []string
+			for _, fe := range verr.Errors {
+				got = append(got, fe.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			// Custom messages are kept as written when localizing
+			got = got[:0]
+			for _, fe := range verr.Localize(valgen.TranslatorFor("de")).Errors {
+				if !fe.Custom {
+					t.Errorf("%s: expected Custom to be set", fe.Rule)
+				}
+				got = append(got, fe.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("localized got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"tests/internal/valgen"
+)
+
+func validUpstream() Upstream {
+	return Upstream{
+		ID:       "123e4567-e89b-12d3-a456-426614174000",
+		Trace:    "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		Endpoint: "https://api.example.com/v1",
+		Docs:     "urn:isbn:0451450523",
+		Host:     "db-1.internal.example.com",
+		Address:  "2001:db8::1",
+		V4:       "192.0.2.10",
+		V6:       "::ffff:192.0.2.10",
+		Subnet:   "10.0.0.0/8",
+		HWAddr:   "00:1a:2b:3c:4d:5e",
+		Port:     5432,
+		Admin:    "8080",
+		Contact:  "ops@example.com",
+		Backup:   "backup@example.com",
+	}
+}
+
+func TestUpstream_NetworkRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Upstream)
+		want   []string
+	}{
+		{name: "valid", modify: func(u *Upstream) {}},
+		{
+			name:   "uuid without hyphens",
+			modify: func(u *Upstream) { u.ID = "123e4567e89b12d3a456426614174000" },
+			want:   []string{"id"},
+		},
+		{
+			name:   "ulid overflows",
+			modify: func(u *Upstream) { u.Trace = "81ARZ3NDEKTSV4RRFFQ69G5FAV" },
+			want:   []string{"trace"},
+		},
+		{
+			name:   "url without host",
+			modify: func(u *Upstream) { u.Endpoint = "/v1/users" },
+			want:   []string{"endpoint"},
+		},
+		{
+			name:   "uri without scheme",
+			modify: func(u *Upstream) { u.Docs = "0451450523" },
+			want:   []string{"docs"},
+		},
+		{
+			name:   "hostname label starts with hyphen",
+			modify: func(u *Upstream) { u.Host = "-db.example.com" },
+			want:   []string{"host"},
+		},
+		{
+			name: "addresses of the wrong family",
+			modify: func(u *Upstream) {
+				u.Address = "fe80::1%eth0"
+				u.V4 = "2001:db8::1"
+				u.V6 = "192.0.2.10"
+			},
+			want: []string{"address", "v4", "v6"},
+		},
+		{
+			name:   "cidr without prefix length",
+			modify: func(u *Upstream) { u.Subnet = "10.0.0.0" },
+			want:   []string{"subnet"},
+		},
+		{
+			name:   "mac too short",
+			modify: func(u *Upstream) { u.HWAddr = "00:1a:2b:3c:4d" },
+			want:   []string{"hwAddr"},
+		},
+		{
+			name: "ports out of range",
+			modify: func(u *Upstream) {
+				u.Port = 70000
+				u.Admin = "08080"
+			},
+			want: []string{"port", "admin"},
+		},
+		{
+			name: "two emails in one struct",
+			modify: func(u *Upstream) {
+				u.Contact = "ops"
+				u.Backup = "backup@"
+			},
+			want: []string{"contact", "backup"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := validUpstream()
+			tt.modify(&u)
+
+			err := u.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var verr *valgen.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *valgen.ValidationError, got %v", err)
+			}
+
+			var got []string
+			for _, fe := range verr.Errors {
+				got = append(got, fe.Field)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpstream_NetworkMessages(t *testing.T) {
+	u := validUpstream()
+	u.Subnet = "10.0.0.0/33"
+
+	if !errors.Is(u.Validate(), valgen.ErrCIDR) {
+		t.Fatalf("expected ErrCIDR")
+	}
+
+	var verr *valgen.ValidationError
+	errors.As(u.Validate(), &verr)
+	if got, want := verr.Errors[0].Message, "subnet must be a valid CIDR prefix"; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,12 @@
+package main
+
+// Orders are generated with -context, so their lines are validated with the
+// caller's context and stop once it is done
+type Order struct {
+	Lines []OrderLine `json:"lines" validate:"dive"`
+}
+
+// OrderLine is validated by a hand-written ValidateContext, see dive_test.go
+type OrderLine struct {
+	SKU string `json:"sku"`
+}
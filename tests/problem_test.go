@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	valgen "tests/internal/valgen"
+)
+
+func TestUser_Validate_ProblemDetails(t *testing.T) {
+	user := User{Name: "", Age: 10, Pwd1: "password", Pwd2: "password", Email: "test@test.com", Color: "blue"}
+	verr := user.Validate().(*valgen.ValidationError)
+
+	rec := httptest.NewRecorder()
+	verr.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users?x=1", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["instance"] != "/users?x=1" || body["status"] != float64(400) {
+		t.Errorf("unexpected problem: %v", body)
+	}
+
+	params, _ := body["invalid-params"].([]any)
+	if len(params) != 2 {
+		t.Fatalf("expected 2 invalid params, got %v", body["invalid-params"])
+	}
+	first := params[0].(map[string]any)
+	if first["name"] != "name" || first["reason"] != "name is required" {
+		t.Errorf("unexpected invalid param: %v", first)
+	}
+}
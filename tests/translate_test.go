@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	valgen "tests/internal/valgen"
+)
+
+func TestUser_Validate_Localize(t *testing.T) {
+	user := User{Name: "", Age: 10, Pwd1: "password", Pwd2: "password", Email: "test@test.com", Color: "blue"}
+
+	verr := user.Validate().(*valgen.ValidationError)
+
+	tests := []struct {
+		lang  string
+		field string
+		want  string
+	}{
+		{lang: "en", field: "age", want: "age must be greater than or equal to 18"},
+		{lang: "de-DE", field: "age", want: "age muss größer oder gleich 18 sein"},
+		{lang: "ja", field: "name", want: "nameは必須です"},
+		{lang: "xx", field: "name", want: "name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			localized := verr.Localize(valgen.TranslatorFor(tt.lang))
+			for _, fe := range localized.Errors {
+				if fe.Field == tt.field && fe.Message != tt.want {
+					t.Errorf("got %q, want %q", fe.Message, tt.want)
+				}
+			}
+		})
+	}
+
+	if verr.Errors[0].Code != "required" {
+		t.Errorf("expected code %q, got %q", "required", verr.Errors[0].Code)
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	if err := valgen.LoadCatalog("fr", strings.NewReader(`{"required": "{field} est obligatoire"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	verr := User{Age: 20, Pwd1: "password", Pwd2: "password", Email: "test@test.com", Color: "blue"}.Validate().(*valgen.ValidationError)
+	got := verr.Localize(valgen.TranslatorFor("fr-CA")).Errors[0].Message
+	if got != "name est obligatoire" {
+		t.Errorf("got %q", got)
+	}
+}
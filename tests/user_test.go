@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	valgen "tests/internal/valgen"
 )
@@ -321,3 +323,65 @@ func TestUser_Validate_ErrorMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_Validate_StructuredErrors(t *testing.T) {
+	user := User{Name: "Test", Age: 20, Pwd1: "password1", Pwd2: "password2", Email: "test@test.com", Color: "blue"}
+
+	verr := user.Validate().(*valgen.ValidationError)
+	if len(verr.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(verr.Errors))
+	}
+
+	fe := verr.Errors[0]
+	want := valgen.FieldError{Field: "pwd2", Path: "pwd2", GoField: "Pwd2", Rule: "eqfieldsecure", Code: "eqfield"}
+	if fe.Field != want.Field || fe.Path != want.Path || fe.GoField != want.GoField || fe.Rule != want.Rule || fe.Code != want.Code {
+		t.Errorf("got %+v, want %+v", fe, want)
+	}
+	if fe.Params["other"] != "Pwd1" {
+		t.Errorf("expected param other=Pwd1, got %v", fe.Params)
+	}
+
+	parent := valgen.NewValidationError("Signup")
+	parent.Nest("user", verr)
+	if got := parent.Errors[0].Path; got != "user.pwd2" {
+		t.Errorf("expected nested path %q, got %q", "user.pwd2", got)
+	}
+}
+
+func TestUser_Validate_RedactsSecrets(t *testing.T) {
+	user := User{Name: "Test", Age: 20, Pwd1: "hunter2", Pwd2: "hunter3", Email: "test@test.com", Color: "blue"}
+
+	verr := user.Validate().(*valgen.ValidationError)
+	for _, fe := range verr.Errors {
+		if fe.Value != nil || !fe.Redacted {
+			t.Errorf("expected %s value to be redacted, got %v", fe.Field, fe.Value)
+		}
+	}
+
+	data, err := verr.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter") {
+		t.Errorf("JSON leaks secret: %s", data)
+	}
+}
+
+func TestUser_Validate_ErrorsIsAs(t *testing.T) {
+	err := User{Name: "Test", Age: 10, Pwd1: "password1", Pwd2: "password2", Email: "test@test.com", Color: "blue"}.Validate()
+
+	if !errors.Is(err, valgen.ErrGte) {
+		t.Error("expected errors.Is to match ErrGte")
+	}
+	if !errors.Is(err, valgen.ErrEqfieldsecure) || !errors.Is(err, valgen.ErrEqfield) {
+		t.Error("expected errors.Is to match the eqfieldsecure rule and its eqfield code")
+	}
+	if errors.Is(err, valgen.ErrRequired) {
+		t.Error("did not expect errors.Is to match ErrRequired")
+	}
+
+	var fe valgen.FieldError
+	if !errors.As(err, &fe) || fe.Field != "age" {
+		t.Errorf("expected errors.As to find the age error, got %+v", fe)
+	}
+}
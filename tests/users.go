@@ -8,3 +8,66 @@ type User struct {
 	Email string `json:"email" validate:"email"`
 	Color string `json:"color" validate:"required"`
 }
+
+// Profile is validated differently on create and on update
+type Profile struct {
+	ID     int    `json:"id" validate:"gt=0@update"`
+	Handle string `json:"handle" validate:"required@create,maxlen=15"`
+	Bio    string `json:"bio" validate:"maxlen=160@update"`
+	Site   string `json:"site" validate:"omitempty,minlen=4"`
+	Team   string `json:"team" validate:"omitempty,contains=team@@support"`
+	Domain string `json:"domain" validate:"omitempty,endswith@update=.example.com"`
+}
+
+// Signup carries consent flags that must be answered
+type Signup struct {
+	Terms     bool  `json:"terms" validate:"istrue"`
+	Robot     bool  `json:"robot" validate:"isfalse"`
+	Marketing *bool `json:"marketing" validate:"required"`
+}
+
+// Coupon exercises the string content rules
+type Coupon struct {
+	Code   string `json:"code" validate:"alphanum,uppercase,len=8"`
+	Link   string `json:"link" validate:"startswith=https://,excludes=\"<"`
+	Region string `json:"region" validate:"omitempty,alpha,lowercase"`
+}
+
+// Upstream exercises the network and identifier format rules
+type Upstream struct {
+	ID       string `json:"id" validate:"uuid"`
+	Trace    string `json:"trace" validate:"omitempty,ulid"`
+	Endpoint string `json:"endpoint" validate:"url"`
+	Docs     string `json:"docs" validate:"omitempty,uri"`
+	Host     string `json:"host" validate:"hostname"`
+	Address  string `json:"address" validate:"ip"`
+	V4       string `json:"v4" validate:"omitempty,ipv4"`
+	V6       string `json:"v6" validate:"omitempty,ipv6"`
+	Subnet   string `json:"subnet" validate:"cidr"`
+	HWAddr   string `json:"hwAddr" validate:"omitempty,mac"`
+	Port     int    `json:"port" validate:"port"`
+	Admin    string `json:"admin" validate:"omitempty,port"`
+	Contact  string `json:"contact" validate:"omitempty,email"`
+	Backup   string `json:"backup" validate:"omitempty,email"`
+}
+
+// Nickname counts its length in runes, the default length mode
+type Nickname struct {
+	Short string `json:"short" validate:"maxlen=3"`
+	Exact string `json:"exact" validate:"omitempty,len=2"`
+}
+
+// Credentials overrides messages with a vmsg tag and a comment directive
+type Credentials struct {
+	Password string `json:"password" validate:"required,minlen=8" vmsg:"minlen=Password too short, use {min}+ characters;required=Enter a password"`
+
+	//valforge:msg eqfield=Passwords do not match
+	Confirm string `json:"confirm" validate:"eqfield=Password"`
+}
+
+// Team validates its members and its lead with their own Validate methods
+type Team struct {
+	Lead    *Nickname           `json:"lead" validate:"dive"`
+	Members []Nickname          `json:"members" validate:"dive"`
+	Roles   map[string]Nickname `json:"roles" validate:"omitempty,dive"`
+}